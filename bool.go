@@ -3,6 +3,7 @@ package gomu
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"reflect"
 )
@@ -140,3 +141,76 @@ func (b Bool) Value() (driver.Value, error) {
 	}
 	return b.Bool, nil
 }
+
+// Set implements flag.Value. "" and "null" set Bool to null,
+// and any other value is parsed with strconv.ParseBool.
+func (b *Bool) Set(s string) error {
+	return b.updateNullable(func(n *Nullable[bool]) error { return n.Set(s) })
+}
+
+// String implements flag.Value.
+func (b Bool) String() string {
+	return b.Nullable().String()
+}
+
+// Get implements flag.Getter. It returns nil if this Bool is null or not valid.
+func (b Bool) Get() interface{} {
+	return b.Nullable().Get()
+}
+
+// Type returns the name of the value type, as used in the usage message of github.com/spf13/pflag.
+func (b Bool) Type() string {
+	return "bool"
+}
+
+// IsBoolFlag makes the flag package accept -name without a value as -name=true.
+func (b Bool) IsBoolFlag() bool {
+	return true
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+// It will encode null if this Bool is null or not valid.
+func (b Bool) MarshalYAML() (interface{}, error) {
+	return b.Nullable().MarshalYAML()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also accepts.
+// Note that both packages leave Bool unassigned for null instead of calling this method;
+// use github.com/hapoon/gomu/yaml to decode null as Null.
+func (b *Bool) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return b.updateNullable(func(n *Nullable[bool]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// IsZero reports whether this Bool is not valid, so that the omitempty option of gopkg.in/yaml.v3
+// and the omitzero option of encoding/json omit unassigned values.
+func (b Bool) IsZero() bool {
+	return b.Nullable().IsZero()
+}
+
+// MarshalXML implements xml.Marshaler.
+// It will encode nothing if this Bool is not valid, and an element with xsi:nil="true" if it is null.
+func (b Bool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return b.Nullable().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+// An element with xsi:nil="true" or without content sets Bool to null.
+func (b *Bool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return b.updateNullable(func(n *Nullable[bool]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr.
+// It will encode no attribute if this Bool is null or not valid.
+func (b Bool) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return b.Nullable().MarshalXMLAttr(name)
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr. An empty attribute sets Bool to null.
+func (b *Bool) UnmarshalXMLAttr(attr xml.Attr) error {
+	return b.updateNullable(func(n *Nullable[bool]) error { return n.UnmarshalXMLAttr(attr) })
+}
+
+func (b Bool) tristate() (null bool, valid bool) {
+	return b.Null, b.Valid
+}
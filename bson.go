@@ -0,0 +1,96 @@
+//go:build bson
+
+package gomu
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// The methods in this file implement bson.ValueMarshaler and bson.ValueUnmarshaler
+// of go.mongodb.org/mongo-driver. They are built with the bson build tag only,
+// so that the package does not depend on the driver otherwise.
+// A missing field leaves the value unassigned, and BSON null sets it to Null.
+// With the default registry an unassigned value is encoded as null,
+// or omitted if its field has the omitempty option, since IsZero reports it as zero.
+// The registry of github.com/hapoon/gomu/bson omits unassigned fields without the option.
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (s String) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return s.Nullable().MarshalBSONValue()
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler.
+func (s *String) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	return s.updateNullable(func(n *Nullable[string]) error { return n.UnmarshalBSONValue(t, data) })
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (i Int) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return i.Nullable().MarshalBSONValue()
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler. BSON int32, int64 and integral double are accepted.
+func (i *Int) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	return i.updateNullable(func(n *Nullable[int64]) error { return n.UnmarshalBSONValue(t, data) })
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (f Float) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return f.Nullable().MarshalBSONValue()
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler. BSON double, int32 and int64 are accepted.
+func (f *Float) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	return f.updateNullable(func(n *Nullable[float64]) error { return n.UnmarshalBSONValue(t, data) })
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (b Bool) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return b.Nullable().MarshalBSONValue()
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler.
+func (b *Bool) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	return b.updateNullable(func(n *Nullable[bool]) error { return n.UnmarshalBSONValue(t, data) })
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler. Time is encoded as a BSON datetime,
+// which has millisecond precision.
+func (t Time) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return t.Nullable().MarshalBSONValue()
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler. The time is decoded in UTC.
+func (t *Time) UnmarshalBSONValue(typ bsontype.Type, data []byte) error {
+	return t.updateNullable(func(n *Nullable[time.Time]) error { return n.UnmarshalBSONValue(typ, data) })
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler. Val is encoded with bson.MarshalValue.
+func (n Nullable[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid || n.Null {
+		return bsontype.Null, nil, nil
+	}
+	return bson.MarshalValue(n.Val)
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler. Val is decoded with bson.UnmarshalValue.
+func (n *Nullable[T]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	*n = Nullable[T]{}
+	if isBSONNull(t) {
+		n.Null = true
+		n.Valid = true
+		return nil
+	}
+	if err := bson.UnmarshalValue(t, data, &n.Val); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func isBSONNull(t bsontype.Type) bool {
+	return t == bsontype.Null || t == bsontype.Undefined
+}
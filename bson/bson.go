@@ -0,0 +1,135 @@
+// Package bson encodes and decodes gomu values with go.mongodb.org/mongo-driver.
+//
+// The registry of NewRegistry has encoders and decoders for String, Int, Float, Bool and Time,
+// which encode Null and unassigned values as null and decode null as Null like UnmarshalJSON does,
+// and omits struct fields holding an unassigned gomu value, as if they had the omitempty option,
+// so that a missing field stays missing across a round trip. Nullable types are registered with RegisterNullable.
+//
+// The gomu types implement bson.ValueMarshaler and bson.ValueUnmarshaler themselves
+// when built with the bson build tag, for the default registry of the driver.
+package bson
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/hapoon/gomu"
+	mongobson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Registry is the registry that Marshal and Unmarshal use.
+var Registry = NewRegistry()
+
+// NewRegistry returns a registry of go.mongodb.org/mongo-driver for the gomu types.
+// Pass it to options.Client().SetRegistry or bson.Encoder.SetRegistry.
+func NewRegistry() *bsoncodec.Registry {
+	r := mongobson.NewRegistry()
+	// NewStructCodec only fails for a nil tag parser.
+	codec, _ := bsoncodec.NewStructCodec(bsoncodec.StructTagParserFunc(parseStructTags))
+	r.RegisterKindEncoder(reflect.Struct, codec)
+	r.RegisterKindDecoder(reflect.Struct, codec)
+	register(r, gomu.String.Nullable, gomu.StringFromNullable)
+	register(r, gomu.Int.Nullable, gomu.IntFromNullable)
+	register(r, gomu.Float.Nullable, gomu.FloatFromNullable)
+	register(r, gomu.Bool.Nullable, gomu.BoolFromNullable)
+	register(r, gomu.Time.Nullable, gomu.TimeFromNullable)
+	return r
+}
+
+// RegisterNullable registers the encoder and decoder of gomu.Nullable[T] in r, such as Registry.
+func RegisterNullable[T any](r *bsoncodec.Registry) {
+	identity := func(n gomu.Nullable[T]) gomu.Nullable[T] { return n }
+	register(r, identity, identity)
+}
+
+// register registers in r the encoder and decoder of the gomu type G, which converts to and from gomu.Nullable[T].
+// The value is encoded and decoded by the codecs of r for T.
+func register[G, T any](r *bsoncodec.Registry, toNullable func(G) gomu.Nullable[T], fromNullable func(gomu.Nullable[T]) G) {
+	t := reflect.TypeOf((*G)(nil)).Elem()
+	r.RegisterTypeEncoder(t, bsoncodec.ValueEncoderFunc(func(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+		n := toNullable(v.Interface().(G))
+		if !n.Valid || n.Null {
+			return vw.WriteNull()
+		}
+		val := reflect.ValueOf(&n.Val).Elem()
+		enc, err := ec.LookupEncoder(val.Type())
+		if err != nil {
+			return err
+		}
+		return enc.EncodeValue(ec, vw, val)
+	}))
+	r.RegisterTypeDecoder(t, bsoncodec.ValueDecoderFunc(func(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+		var n gomu.Nullable[T]
+		switch vr.Type() {
+		case bsontype.Null:
+			if err := vr.ReadNull(); err != nil {
+				return err
+			}
+			n.Null = true
+		case bsontype.Undefined:
+			if err := vr.ReadUndefined(); err != nil {
+				return err
+			}
+			n.Null = true
+		default:
+			val := reflect.ValueOf(&n.Val).Elem()
+			dec, err := dc.LookupDecoder(val.Type())
+			if err != nil {
+				return err
+			}
+			if err = dec.DecodeValue(dc, vr, val); err != nil {
+				return err
+			}
+		}
+		n.Valid = true
+		v.Set(reflect.ValueOf(fromNullable(n)))
+		return nil
+	}))
+}
+
+// parseStructTags parses the bson tag of sf like bsoncodec.DefaultStructTagParser,
+// and sets OmitEmpty for gomu fields.
+func parseStructTags(sf reflect.StructField) (bsoncodec.StructTags, error) {
+	tags, err := bsoncodec.DefaultStructTagParser(sf)
+	if err == nil && gomu.IsType(sf.Type) {
+		tags.OmitEmpty = true
+	}
+	return tags, err
+}
+
+// Marshal returns the BSON document of v like bson.Marshal with Registry,
+// so struct fields whose gomu value is not Valid are omitted. Null values are still encoded as null.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	vw, err := bsonrw.NewBSONValueWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := mongobson.NewEncoder(vw)
+	if err != nil {
+		return nil, err
+	}
+	if err = enc.SetRegistry(Registry); err != nil {
+		return nil, err
+	}
+	if err = enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the BSON document data into v like bson.Unmarshal with Registry.
+// gomu values whose field is missing are left unassigned (Valid is false), and null sets them to Null.
+func Unmarshal(data []byte, v interface{}) error {
+	dec, err := mongobson.NewDecoder(bsonrw.NewBSONDocumentReader(data))
+	if err != nil {
+		return err
+	}
+	if err = dec.SetRegistry(Registry); err != nil {
+		return err
+	}
+	return dec.Decode(v)
+}
@@ -0,0 +1,108 @@
+package bson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+	mongobson "go.mongodb.org/mongo-driver/bson"
+)
+
+func init() {
+	RegisterNullable[int](Registry)
+}
+
+type testStructBSON struct {
+	Name   gomu.String        `bson:"name"`
+	Age    gomu.Int           `bson:"age"`
+	Ratio  gomu.Float         `bson:"ratio,omitempty"`
+	Active gomu.Bool          `bson:"active,omitempty"`
+	Born   gomu.Time          `bson:"born,omitempty"`
+	Score  gomu.Nullable[int] `bson:"score,omitempty"`
+}
+
+func TestBSON(t *testing.T) {
+	born := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	src := testStructBSON{
+		Name:   gomu.StringFrom("foo"),
+		Age:    gomu.NewInt(0, true, true),
+		Active: gomu.BoolFrom(true),
+		Born:   gomu.TimeFrom(born),
+		Score:  gomu.NewNullable(0, true, true),
+	}
+	data, err := Marshal(src)
+	assert.NoError(t, err, "Marshal fail")
+
+	var doc mongobson.M
+	err = mongobson.Unmarshal(data, &doc)
+	assert.NoError(t, err, "bson.Unmarshal fail")
+	assert.Equal(t, "foo", doc["name"], "name fail")
+	assert.Contains(t, doc, "age", "Null age fail")
+	assert.Nil(t, doc["age"], "Null age value fail")
+	assert.NotContains(t, doc, "ratio", "unassigned ratio fail")
+	assert.IsType(t, born, doc["born"].(interface{ Time() time.Time }).Time(), "born datetime fail")
+	assert.Contains(t, doc, "score", "Null score fail")
+
+	var target testStructBSON
+	err = Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, src, target, "round trip fail")
+
+	// int32 and double
+	data, err = mongobson.Marshal(mongobson.M{"age": int32(3), "ratio": 2, "score": 4})
+	assert.NoError(t, err, "bson.Marshal fail")
+	target = testStructBSON{}
+	err = Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, gomu.IntFrom(3), target.Age, "int32 fail")
+	assert.Equal(t, gomu.FloatFrom(2), target.Ratio, "int to Float fail")
+	assert.Equal(t, gomu.NullableFrom(4), target.Score, "Nullable fail")
+	assert.False(t, target.Name.Valid, "missing fail")
+
+	data, err = mongobson.Marshal(mongobson.M{"age": 1.5})
+	assert.NoError(t, err, "bson.Marshal fail")
+	err = Unmarshal(data, &target)
+	assert.Error(t, err, "non-integral double fail")
+
+	data, err = mongobson.Marshal(mongobson.M{"name": 1})
+	assert.NoError(t, err, "bson.Marshal fail")
+	err = Unmarshal(data, &target)
+	assert.Error(t, err, "type mismatch fail")
+}
+
+func TestMarshal(t *testing.T) {
+	type testStructMarshalBSON struct {
+		Inner testStructBSON `bson:",inline"`
+		Note  gomu.String    `bson:"note"`
+		Ptr   *gomu.String   `bson:"ptr"`
+		Plain string         `bson:"plain"`
+	}
+	// Inner.Name, Inner.Age and Note have no omitempty option
+	src := testStructMarshalBSON{
+		Inner: testStructBSON{Age: gomu.NewInt(0, true, true), Ratio: gomu.FloatFrom(1.5)},
+		Note:  gomu.StringFromPtr(nil),
+	}
+	data, err := Marshal(src)
+	assert.NoError(t, err, "Marshal fail")
+
+	var doc mongobson.M
+	err = mongobson.Unmarshal(data, &doc)
+	assert.NoError(t, err, "bson.Unmarshal fail")
+	assert.NotContains(t, doc, "name", "unassigned name fail")
+	assert.Contains(t, doc, "age", "Null age fail")
+	assert.Nil(t, doc["age"], "Null age value fail")
+	assert.Equal(t, 1.5, doc["ratio"], "ratio fail")
+	assert.Contains(t, doc, "note", "Null note fail")
+	assert.Contains(t, doc, "ptr", "nil pointer fail")
+	assert.Contains(t, doc, "plain", "plain fail")
+
+	// unassigned values stay unassigned across a round trip
+	var target testStructMarshalBSON
+	err = Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, src, target, "round trip fail")
+
+	_, err = Marshal("string")
+	assert.Error(t, err, "Marshal(string) fail")
+}
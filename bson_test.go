@@ -0,0 +1,68 @@
+//go:build bson
+
+package gomu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type testStructBSON struct {
+	Name   String        `bson:"name"`
+	Age    Int           `bson:"age"`
+	Ratio  Float         `bson:"ratio,omitempty"`
+	Active Bool          `bson:"active,omitempty"`
+	Born   Time          `bson:"born,omitempty"`
+	Score  Nullable[int] `bson:"score,omitempty"`
+}
+
+func TestBSON(t *testing.T) {
+	born := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	src := testStructBSON{
+		Name:   StringFrom("foo"),
+		Age:    NewInt(0, true, true),
+		Active: BoolFrom(true),
+		Born:   TimeFrom(born),
+		Score:  NewNullable(0, true, true),
+	}
+	data, err := bson.Marshal(src)
+	checkError(err)
+
+	var doc bson.M
+	err = bson.Unmarshal(data, &doc)
+	checkError(err)
+	assert.Equal(t, "foo", doc["name"], "name fail")
+	assert.Contains(t, doc, "age", "Null age fail")
+	assert.Nil(t, doc["age"], "Null age value fail")
+	assert.NotContains(t, doc, "ratio", "unassigned ratio fail")
+	assert.IsType(t, born, doc["born"].(interface{ Time() time.Time }).Time(), "born datetime fail")
+
+	var target testStructBSON
+	err = bson.Unmarshal(data, &target)
+	checkError(err)
+	assert.Equal(t, src, target, "round trip fail")
+
+	// int32 and double
+	data, err = bson.Marshal(bson.M{"age": int32(3), "ratio": 2, "score": 4})
+	checkError(err)
+	target = testStructBSON{}
+	err = bson.Unmarshal(data, &target)
+	checkError(err)
+	assert.Equal(t, IntFrom(3), target.Age, "int32 fail")
+	assert.Equal(t, FloatFrom(2), target.Ratio, "int to Float fail")
+	assert.Equal(t, NullableFrom(4), target.Score, "Nullable fail")
+	assert.False(t, target.Name.Valid, "missing fail")
+
+	data, err = bson.Marshal(bson.M{"age": 1.5})
+	checkError(err)
+	err = bson.Unmarshal(data, &target)
+	assert.Error(t, err, "non-integral double fail")
+
+	data, err = bson.Marshal(bson.M{"name": 1})
+	checkError(err)
+	err = bson.Unmarshal(data, &target)
+	assert.Error(t, err, "type mismatch fail")
+}
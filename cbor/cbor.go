@@ -0,0 +1,405 @@
+// Package cbor encodes and decodes gomu values with github.com/fxamacker/cbor/v2.
+//
+// fxamacker/cbor has no hook for types of other packages, so Marshal and Unmarshal walk structs,
+// maps and slices themselves, and leave the other values to the cbor package.
+// Null gomu values are encoded as null, unassigned ones are omitted from structs and maps,
+// and null (or undefined) is decoded as Null like UnmarshalJSON does.
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hapoon/gomu"
+	"github.com/hapoon/gomu/internal/structtag"
+)
+
+// encMode encodes time.Time as an RFC 3339 string with tag 0, so that no precision is lost.
+var encMode, _ = cbor.EncOptions{Time: cbor.TimeRFC3339Nano, TimeTag: cbor.EncTagRequired}.EncMode()
+
+// Marshal returns the CBOR encoding of v like cbor.Marshal,
+// except that struct fields and map entries holding a gomu value that is not Valid are omitted,
+// and Null gomu values are encoded as null.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the CBOR-encoded data into v, which must be a non-nil pointer, like cbor.Unmarshal.
+// gomu values whose key is missing are left unassigned (Valid is false), and null sets them to Null.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("gomu: cbor.Unmarshal requires a non-nil pointer; got %T", v)
+	}
+	return decodeValue(data, rv.Elem())
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	rawMessageType  = reflect.TypeOf(cbor.RawMessage(nil))
+	marshalerType   = reflect.TypeOf((*cbor.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*cbor.Unmarshaler)(nil)).Elem()
+)
+
+// isLeaf reports whether values of type t are encoded and decoded by the cbor package as a whole.
+func isLeaf(t reflect.Type) bool {
+	return t == timeType ||
+		t.Implements(marshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)
+}
+
+// isNull reports whether raw is the CBOR null or undefined.
+func isNull(raw []byte) bool {
+	return len(raw) == 1 && (raw[0] == 0xf6 || raw[0] == 0xf7)
+}
+
+// writeHead writes the head of a data item of the major type with argument n.
+func writeHead(buf *bytes.Buffer, major byte, n uint64) {
+	m := major << 5
+	switch {
+	case n < 24:
+		buf.WriteByte(m | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{m | 24, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(m | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		buf.WriteByte(m | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(m | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+const (
+	majorArray = 4
+	majorMap   = 5
+)
+
+func encodeDefault(buf *bytes.Buffer, v interface{}) error {
+	b, err := encMode.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+func encodeValue(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteByte(0xf6)
+		return nil
+	}
+	t := v.Type()
+	if gomu.IsType(t) {
+		if null, valid, _ := gomu.Tristate(v); !valid || null {
+			buf.WriteByte(0xf6)
+			return nil
+		}
+		return encodeDefault(buf, v.Field(0).Interface())
+	}
+	if isLeaf(t) {
+		return encodeDefault(buf, v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteByte(0xf6)
+			return nil
+		}
+		return encodeValue(buf, v.Elem())
+	case reflect.Struct:
+		return encodeStruct(buf, v)
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteByte(0xf6)
+			return nil
+		}
+		var entries bytes.Buffer
+		n := 0
+		iter := v.MapRange()
+		for iter.Next() {
+			if gomu.IsUnassigned(iter.Value()) {
+				continue
+			}
+			if err := encodeDefault(&entries, iter.Key().Interface()); err != nil {
+				return err
+			}
+			if err := encodeValue(&entries, iter.Value()); err != nil {
+				return err
+			}
+			n++
+		}
+		writeHead(buf, majorMap, uint64(n))
+		buf.Write(entries.Bytes())
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || t.Elem().Kind() == reflect.Uint8) {
+			return encodeDefault(buf, v.Interface())
+		}
+		writeHead(buf, majorArray, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return encodeDefault(buf, v.Interface())
+	}
+}
+
+type field struct {
+	name      string
+	index     []int
+	keyAsInt  bool
+	omitEmpty bool
+}
+
+func encodeStruct(buf *bytes.Buffer, v reflect.Value) error {
+	fields, ok := structFields(v.Type(), nil)
+	if !ok {
+		return encodeDefault(buf, v.Interface())
+	}
+	var entries bytes.Buffer
+	n := 0
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || gomu.IsUnassigned(fv) || f.omitEmpty && isEmpty(fv) {
+			continue
+		}
+		var key interface{} = f.name
+		if f.keyAsInt {
+			var i int64
+			if _, err := fmt.Sscan(f.name, &i); err != nil {
+				return fmt.Errorf("gomu: invalid keyasint field name %q", f.name)
+			}
+			key = i
+		}
+		if err := encodeDefault(&entries, key); err != nil {
+			return err
+		}
+		if err := encodeValue(&entries, fv); err != nil {
+			return err
+		}
+		n++
+	}
+	writeHead(buf, majorMap, uint64(n))
+	buf.Write(entries.Bytes())
+	return nil
+}
+
+// structFields returns the fields of struct type t following the rules of the cbor package:
+// the key is the name in the `cbor` tag, the `json` tag or the field name,
+// and embedded structs without a name are inlined.
+// It returns false if t is encoded as an array by the toarray option.
+func structFields(t reflect.Type, index []int) ([]field, bool) {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("cbor")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		name, opts := structtag.Parse(tag)
+		if sf.Name == "_" && structtag.HasOption(opts, "toarray") {
+			return nil, false
+		}
+		if name == "-" || sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && !gomu.IsType(ft) && !isLeaf(ft) {
+			inlined, ok := structFields(ft, idx)
+			if !ok {
+				return nil, false
+			}
+			fields = append(fields, inlined...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{
+			name:      name,
+			index:     idx,
+			keyAsInt:  structtag.HasOption(opts, "keyasint"),
+			omitEmpty: structtag.HasOption(opts, "omitempty"),
+		})
+	}
+	return fields, true
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex but returns false for fields of nil embedded pointers,
+// or allocates them if alloc is true.
+func fieldByIndexAlloc(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	return fieldByIndexAlloc(v, index, false)
+}
+
+// isEmpty reports whether v is empty in the sense of the cbor omitempty option.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		if gomu.IsType(v.Type()) {
+			return gomu.IsUnassigned(v)
+		}
+	}
+	return v.IsZero()
+}
+
+func decodeValue(raw []byte, v reflect.Value) error {
+	t := v.Type()
+	if gomu.IsType(t) {
+		v.Set(reflect.Zero(t))
+		if isNull(raw) {
+			v.FieldByName("Null").SetBool(true)
+		} else if err := cbor.Unmarshal(raw, v.Field(0).Addr().Interface()); err != nil {
+			return err
+		}
+		v.FieldByName("Valid").SetBool(true)
+		return nil
+	}
+	if isLeaf(t) {
+		return cbor.Unmarshal(raw, v.Addr().Interface())
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if isNull(raw) {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return decodeValue(raw, v.Elem())
+	case reflect.Struct:
+		if isNull(raw) {
+			return nil
+		}
+		return decodeStruct(raw, v)
+	case reflect.Map:
+		if isNull(raw) {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		m := reflect.New(reflect.MapOf(t.Key(), rawMessageType))
+		if err := cbor.Unmarshal(raw, m.Interface()); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, m.Elem().Len()))
+		}
+		iter := m.Elem().MapRange()
+		for iter.Next() {
+			e := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(iter.Value().Bytes(), e); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), e)
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return cbor.Unmarshal(raw, v.Addr().Interface())
+		}
+		if isNull(raw) {
+			if v.Kind() == reflect.Slice {
+				v.Set(reflect.Zero(t))
+			}
+			return nil
+		}
+		var elems []cbor.RawMessage
+		if err := cbor.Unmarshal(raw, &elems); err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+		}
+		for i := 0; i < len(elems) && i < v.Len(); i++ {
+			if err := decodeValue(elems[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return cbor.Unmarshal(raw, v.Addr().Interface())
+	}
+}
+
+func decodeStruct(raw []byte, v reflect.Value) error {
+	fields, ok := structFields(v.Type(), nil)
+	if !ok {
+		return cbor.Unmarshal(raw, v.Addr().Interface())
+	}
+	var m map[interface{}]cbor.RawMessage
+	if err := cbor.Unmarshal(raw, &m); err != nil {
+		return err
+	}
+	for key, fraw := range m {
+		f, ok := findField(fields, fmt.Sprint(key))
+		if !ok {
+			continue
+		}
+		fv, ok := fieldByIndexAlloc(v, f.index, true)
+		if !ok {
+			continue
+		}
+		if err := decodeValue(fraw, fv); err != nil {
+			return gomu.Error{Name: f.name, Path: f.name, Err: err}
+		}
+	}
+	return nil
+}
+
+// findField returns the field named key, preferring an exact match over a case-insensitive one.
+func findField(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if !f.keyAsInt && strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
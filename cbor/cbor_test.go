@@ -0,0 +1,96 @@
+package cbor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructCBORBase struct {
+	ID gomu.Int `cbor:"id"`
+}
+
+type testStructCBOR struct {
+	testStructCBORBase
+	Name   gomu.String         `json:"name"`
+	Age    gomu.Int            `cbor:"age"`
+	Ratio  gomu.Float          `cbor:"ratio"`
+	Active gomu.Bool           `cbor:"active"`
+	Born   gomu.Time           `cbor:"born"`
+	Score  gomu.Nullable[int]  `cbor:"score"`
+	Labels map[string]gomu.Int `cbor:"labels"`
+	Tags   []gomu.String       `cbor:"tags"`
+	Child  *testStructCBORBase `cbor:"child"`
+	Code   int                 `cbor:"1,keyasint,omitempty"`
+}
+
+func TestMarshal(t *testing.T) {
+	born := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	src := testStructCBOR{
+		testStructCBORBase: testStructCBORBase{ID: gomu.IntFrom(1)},
+		Name:               gomu.StringFrom("foo"),
+		Age:                gomu.NewInt(0, true, true),
+		Born:               gomu.TimeFrom(born),
+		Score:              gomu.NullableFrom(3),
+		Labels:             map[string]gomu.Int{"x": gomu.IntFrom(1), "y": {}},
+		Tags:               []gomu.String{gomu.StringFrom("a"), gomu.NewString("", true, true)},
+		Child:              &testStructCBORBase{ID: gomu.NewInt(0, true, true)},
+		Code:               7,
+	}
+	data, err := Marshal(src)
+	assert.NoError(t, err, "Marshal fail")
+
+	var keys map[interface{}]interface{}
+	err = cbor.Unmarshal(data, &keys)
+	assert.NoError(t, err, "Unmarshal keys fail")
+	assert.Contains(t, keys, "age", "Null age fail")
+	assert.Nil(t, keys["age"], "Null age value fail")
+	assert.NotContains(t, keys, "ratio", "unassigned ratio fail")
+	assert.NotContains(t, keys, "active", "unassigned active fail")
+	assert.Equal(t, map[interface{}]interface{}{"x": uint64(1)}, keys["labels"], "labels fail")
+	assert.Equal(t, uint64(7), keys[uint64(1)], "keyasint fail")
+
+	var target testStructCBOR
+	err = Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, src.ID, target.ID, "ID fail")
+	assert.Equal(t, src.Name, target.Name, "Name fail")
+	assert.Equal(t, src.Age, target.Age, "Age fail")
+	assert.False(t, target.Ratio.Valid, "Ratio fail")
+	assert.False(t, target.Active.Valid, "Active fail")
+	assert.True(t, born.Equal(target.Born.Time), "Born fail")
+	assert.Equal(t, src.Score, target.Score, "Score fail")
+	assert.Equal(t, map[string]gomu.Int{"x": gomu.IntFrom(1)}, target.Labels, "Labels fail")
+	assert.Equal(t, src.Tags, target.Tags, "Tags fail")
+	assert.Equal(t, src.Child, target.Child, "Child fail")
+	assert.Equal(t, 7, target.Code, "Code fail")
+}
+
+func TestUnmarshal(t *testing.T) {
+	data, err := cbor.Marshal(map[string]interface{}{"name": "foo", "age": nil, "Active": true})
+	checkError(err)
+	var target testStructCBOR
+	err = Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, gomu.StringFrom("foo"), target.Name, "Name fail")
+	assert.Equal(t, gomu.NewInt(0, true, true), target.Age, "Age fail")
+	assert.Equal(t, gomu.BoolFrom(true), target.Active, "Active case-insensitive fail")
+	assert.False(t, target.ID.Valid, "ID fail")
+
+	data, err = cbor.Marshal(map[string]interface{}{"age": "x"})
+	checkError(err)
+	err = Unmarshal(data, &target)
+	assert.Error(t, err, "type mismatch fail")
+
+	err = Unmarshal(data, target)
+	assert.Error(t, err, "non-pointer fail")
+}
+
+func checkError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
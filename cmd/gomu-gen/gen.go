@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const gomuPkgPath = "github.com/hapoon/gomu"
+
+// valueFields maps the gomu types to the field holding their value.
+var valueFields = map[string]string{
+	"String":   "String",
+	"Int":      "Int64",
+	"Float":    "Float64",
+	"Bool":     "Bool",
+	"Time":     "Time",
+	"Nullable": "Val",
+}
+
+// validator is a built-in validator of a gomu type that is compiled into a call of fn.
+type validator struct {
+	fn     string
+	params bool // whether the tag parameters are passed to fn
+}
+
+// validators holds the validators of TypeTagMap, TagMap and ParamTagMap by gomu type.
+var validators = map[string]map[string]validator{
+	"String": {
+		"url":          {fn: "IsURL"},
+		"requrl":       {fn: "IsRequestURL"},
+		"requri":       {fn: "IsRequestURI"},
+		"length":       {fn: "ByteLength", params: true},
+		"stringlength": {fn: "StringLength", params: true},
+	},
+	"Int": {
+		"range":    {fn: "IntRange", params: true},
+		"min":      {fn: "IntMin", params: true},
+		"max":      {fn: "IntMax", params: true},
+		"positive": {fn: "IsPositive"},
+		"in":       {fn: "IntIn", params: true},
+	},
+	"Time": {
+		"before": {fn: "TimeBefore", params: true},
+		"after":  {fn: "TimeAfter", params: true},
+		"past":   {fn: "IsPast"},
+		"future": {fn: "IsFuture"},
+	},
+	"Bool": {
+		"eq": {fn: "BoolEq", params: true},
+	},
+}
+
+// rxParamTag matches the validators of ParamTagMap, which only accept digits as parameters.
+var rxParamTag = regexp.MustCompile(`^(length|stringlength)\((\d+)\|(\d+)\)$`)
+
+// generate returns the source of the Validate methods of the struct types of the package in dir.
+// If names is empty, the methods are generated for every struct type that has valid tags,
+// directly or in the struct types of the package it nests. output is the file to be written,
+// which is ignored while loading the package.
+func generate(dir, output string, names []string) ([]byte, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes, Dir: dir}
+	if src, err := os.ReadFile(output); err == nil {
+		f, err := parser.ParseFile(token.NewFileSet(), output, src, parser.PackageClauseOnly)
+		if err != nil {
+			return nil, err
+		}
+		abs, err := filepath.Abs(output)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Overlay = map[string][]byte{abs: []byte("package " + f.Name.Name + "\n")}
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%d packages found in %s", len(pkgs), dir)
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, pkg.Errors[0]
+	}
+
+	g := &generator{pkg: pkg.Types, types: make(map[*types.TypeName]bool), imports: make(map[string]bool)}
+	scope := pkg.Types.Scope()
+	if len(names) == 0 {
+		for _, name := range scope.Names() {
+			if named := g.structType(scope.Lookup(name)); named != nil && g.hasValidTags(named) {
+				g.types[named.Obj()] = true
+			}
+		}
+	}
+	for _, name := range names {
+		named := g.structType(scope.Lookup(name))
+		if named == nil {
+			return nil, fmt.Errorf("%s is not a struct type of package %s", name, pkg.Name)
+		}
+		g.types[named.Obj()] = true
+	}
+	objs := make([]*types.TypeName, 0, len(g.types))
+	for obj := range g.types {
+		objs = append(objs, obj)
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Name() < objs[j].Name() })
+	for _, obj := range objs {
+		g.method(obj.Type().(*types.Named))
+	}
+	return g.source()
+}
+
+type generator struct {
+	pkg     *types.Package
+	types   map[*types.TypeName]bool // the types whose methods are generated
+	imports map[string]bool
+	buf     bytes.Buffer
+}
+
+// structType returns obj if it is a named struct type without type parameters.
+func (g *generator) structType(obj types.Object) *types.Named {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || tn.IsAlias() {
+		return nil
+	}
+	named, ok := tn.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named
+}
+
+func (g *generator) hasValidTags(named *types.Named) bool {
+	st := named.Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		if !st.Field(i).Exported() {
+			continue
+		}
+		if tag := reflect.StructTag(st.Tag(i)).Get("valid"); tag != "" && tag != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) method(named *types.Named) {
+	name := named.Obj().Name()
+	g.printf("// Validate validates t by its valid tags as gomu.Validate does, and returns gomu.Errors if it is not valid.\n")
+	g.printf("func (t %s) Validate() error {\n", name)
+	g.printf("if errs := t.gomuValidate(\"\"); len(errs) > 0 {\nreturn errs\n}\nreturn nil\n}\n\n")
+	g.printf("func (t %s) gomuValidate(path string) (errs gomu.Errors) {\n", name)
+	st := named.Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		if f := st.Field(i); f.Exported() {
+			g.field(f, reflect.StructTag(st.Tag(i)))
+		}
+	}
+	g.printf("return\n}\n\n")
+}
+
+// field writes the validation of the field f, following typeCheck of gomu.
+func (g *generator) field(f *types.Var, tag reflect.StructTag) {
+	g.imports[gomuPkgPath] = true
+	path := "path"
+	jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+	if jsonName == "" || jsonName == "-" {
+		jsonName = f.Name()
+		if f.Embedded() {
+			jsonName = ""
+		}
+	}
+	if jsonName != "" {
+		path = fmt.Sprintf("gomu.JoinPath(path, %q)", jsonName)
+	}
+	validTag := tag.Get("valid")
+	if validTag == "" || validTag == "-" {
+		return
+	}
+	if typ, ok := gomuType(f.Type()); ok && g.compile(f, typ, validTag, path) {
+		return
+	}
+	g.printf("errs = append(errs, gomu.CheckTag(&t.%s, t, %q, %q, %s)...)\n", f.Name(), f.Name(), validTag, path)
+}
+
+// gomuType returns the name of the gomu type t.
+func gomuType(t types.Type) (string, bool) {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != gomuPkgPath {
+		return "", false
+	}
+	name := named.Obj().Name()
+	_, ok = valueFields[name]
+	return name, ok
+}
+
+type option struct {
+	key     string
+	message string
+}
+
+// parseTag splits tag into its options in order, like tagPlanOf of gomu.
+func parseTag(tag string) []option {
+	var options []option
+	index := make(map[string]int)
+	for _, o := range strings.Split(tag, ",") {
+		parts := strings.Split(o, "~")
+		if !isValidTag(parts[0]) {
+			continue
+		}
+		message := ""
+		if len(parts) == 2 {
+			message = parts[1]
+		}
+		if i, ok := index[parts[0]]; ok {
+			options[i].message = message
+			continue
+		}
+		index[parts[0]] = len(options)
+		options = append(options, option{key: parts[0], message: message})
+	}
+	return options
+}
+
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("!#$%&()*+-./:<=>?@[]^_{|}~ ", c) && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// compile writes the validation of the gomu field f of type typ as a switch statement whose cases
+// are in the order of typeCheck. It reports false if an option is not built in,
+// in which case the field is left to gomu.CheckTag at run time.
+func (g *generator) compile(f *types.Var, typ, tag, path string) bool {
+	options := parseTag(tag)
+	byKey := make(map[string]option, len(options))
+	for _, o := range options {
+		byKey[o.key] = o
+	}
+	var cases []string
+	addCase := func(cond string, e errorSpec) {
+		e.name, e.path = f.Name(), path
+		cases = append(cases, fmt.Sprintf("case %s:\nerrs = append(errs, %s)\n", cond, g.errorLit(e)))
+	}
+
+	_, omit := byKey["omitunassigned"]
+	if omit {
+		cases = append(cases, "case !v.Valid: // omitunassigned\n")
+	} else if o, ok := byKey["present"]; ok {
+		addCase("!v.Valid", errorSpec{validator: "present", message: o.message, defaultErr: `errors.New("value must be present")`})
+	}
+	if o, ok := byKey["notnull"]; ok {
+		addCase("v.Valid && v.Null", errorSpec{validator: "notnull", message: o.message, defaultErr: `errors.New("value must not be null")`})
+	}
+	emptyCase := "case v.Null || !v.Valid: // the validators only apply to non-null values\n"
+	if o, ok := byKey["required"]; ok {
+		addCase("v.Null || !v.Valid", errorSpec{validator: "required", message: o.message, defaultErr: `errors.New("non zero value required")`, defaultCustom: true})
+		emptyCase = ""
+	}
+
+	value := "v." + valueFields[typ]
+	var validatorCases []string
+	for _, o := range options {
+		switch o.key {
+		case "omitunassigned", "present", "notnull", "required":
+			continue
+		}
+		key, negate := o.key, false
+		if key[0] == '!' {
+			key, negate = key[1:], true
+		}
+		name, params := parseValidatorTag(key)
+		v, ok := validators[typ][name]
+		if !ok {
+			return false
+		}
+		if typ == "String" {
+			// TagMap validators match the whole key, and ParamTagMap ones match their regexp.
+			if !v.params && key != name {
+				return false
+			}
+			if v.params {
+				m := rxParamTag.FindStringSubmatch(key)
+				if m == nil {
+					return false
+				}
+				params = m[2:]
+			}
+		}
+		args := []string{value}
+		if v.params {
+			for _, p := range params {
+				args = append(args, strconv.Quote(p))
+			}
+		}
+		call := fmt.Sprintf("gomu.%s(%s)", v.fn, strings.Join(args, ", "))
+		cond, verb, validatorName := "!"+call, "does not validate", name
+		if negate {
+			cond, verb, validatorName = call, "does validate", "!"+name
+		}
+		g.imports["fmt"] = true
+		e := errorSpec{name: f.Name(), path: path, validator: validatorName, params: params, message: o.message,
+			defaultErr: fmt.Sprintf("fmt.Errorf(\"%%s %s as %%s\", fmt.Sprint(%s), %q)", verb, value, key)}
+		validatorCases = append(validatorCases, fmt.Sprintf("case %s:\nerrs = append(errs, %s)\n", cond, g.errorLit(e)))
+	}
+	if len(validatorCases) > 0 {
+		cases = append(append(cases, emptyCase), validatorCases...)
+	}
+	if len(cases) == 0 {
+		return true
+	}
+	g.printf("switch v := t.%s; {\n%s}\n", f.Name(), strings.Join(cases, ""))
+	return true
+}
+
+// errorSpec describes a gomu.Error of a field.
+type errorSpec struct {
+	name, path, validator string
+	params                []string
+	message               string // the custom error message of the tag
+	defaultErr            string // the expression of the error without a custom message
+	defaultCustom         bool   // whether CustomErrorMessageExists is set without a custom message
+}
+
+// errorLit returns the gomu.Error literal of e.
+func (g *generator) errorLit(e errorSpec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gomu.Error{Name: %q, Path: %s, Validator: %q", e.name, e.path, e.validator)
+	if len(e.params) > 0 {
+		quoted := make([]string, len(e.params))
+		for i, p := range e.params {
+			quoted[i] = strconv.Quote(p)
+		}
+		fmt.Fprintf(&b, ", Params: []string{%s}", strings.Join(quoted, ", "))
+	}
+	switch {
+	case e.message != "":
+		fmt.Fprintf(&b, ", Err: errors.New(%q), CustomErrorMessageExists: true}", e.message)
+	case e.defaultCustom:
+		fmt.Fprintf(&b, ", Err: %s, CustomErrorMessageExists: true}", e.defaultErr)
+	default:
+		fmt.Fprintf(&b, ", Err: %s}", e.defaultErr)
+	}
+	if strings.Contains(b.String(), "errors.New") {
+		g.imports["errors"] = true
+	}
+	return b.String()
+}
+
+// parseValidatorTag splits a validator such as "range(1|10)" into its name and parameters, like gomu does.
+func parseValidatorTag(validator string) (name string, params []string) {
+	i := strings.IndexByte(validator, '(')
+	if i <= 0 || !strings.HasSuffix(validator, ")") {
+		return validator, nil
+	}
+	return validator[:i], strings.Split(validator[i+1:len(validator)-1], "|")
+}
+
+func (g *generator) source() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gomu-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.pkg.Name())
+	paths := make([]string, 0, len(g.imports))
+	for path, used := range g.imports {
+		if used {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	b.WriteString("import (\n")
+	for _, path := range paths {
+		if path == gomuPkgPath && len(paths) > 1 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString(")\n\n")
+	b.Write(g.buf.Bytes())
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	output := filepath.Join(dir, "gomu_validate.go")
+	src, err := generate(dir, output, nil)
+	assert.NoError(t, err, "generate fail")
+	expect, err := os.ReadFile(output)
+	assert.NoError(t, err, "ReadFile fail")
+	assert.Equal(t, string(expect), string(src), "generate fail; run go generate ./cmd/gomu-gen/internal/example")
+
+	src, err = generate(dir, output, []string{"Item"})
+	assert.NoError(t, err, "generate(Item) fail")
+	assert.Contains(t, string(src), "func (t Item) Validate() error", "generate(Item) fail")
+	assert.NotContains(t, string(src), "func (t User) Validate() error", "generate(Item) User fail")
+
+	_, err = generate(dir, output, []string{"Unknown"})
+	assert.Error(t, err, "generate(Unknown) fail")
+}
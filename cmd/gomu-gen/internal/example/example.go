@@ -0,0 +1,47 @@
+// Package example holds the structs whose Validate methods gomu-gen generates in its tests.
+package example
+
+import (
+	"github.com/hapoon/gomu"
+)
+
+//go:generate go run github.com/hapoon/gomu/cmd/gomu-gen
+
+type Base struct {
+	ID gomu.Int `json:"id" valid:"required,positive"`
+}
+
+type User struct {
+	Base     `valid:"nested"`
+	Name     gomu.String            `json:"name" valid:"stringlength(1|10),required"`
+	Nickname gomu.String            `json:"nickname" valid:"present,notnull~nickname must not be null"`
+	Homepage gomu.String            `json:"homepage" valid:"requrl,omitunassigned"`
+	Code     gomu.String            `json:"code" valid:"!length(3|3)"`
+	Age      gomu.Int               `json:"age" valid:"range(0|150)~age is out of range"`
+	Rank     gomu.Int               `json:"rank" valid:"in(1|2|3)"`
+	Agreed   gomu.Bool              `json:"agreed" valid:"eq(true)"`
+	Born     gomu.Time              `json:"born" valid:"past"`
+	Ratio    gomu.Float             `json:"ratio" valid:"notnull"`
+	Score    gomu.Nullable[int]     `json:"score" valid:"required"`
+	Even     gomu.Int               `json:"even" valid:"even"`
+	Count    int                    `json:"count" valid:"required"`
+	Address  *Address               `json:"address" valid:"nested"`
+	Items    []Item                 `json:"items" valid:"nested"`
+	Extra    *Extra                 `json:"extra"`
+	Meta     map[string]gomu.String `json:"meta"`
+	Ignored  gomu.String            `json:"ignored" valid:"-"`
+	internal gomu.String            `valid:"required"`
+}
+
+type Address struct {
+	Zip gomu.String `json:"zip" valid:"required,stringlength(1|8)"`
+}
+
+type Item struct {
+	SKU gomu.String `json:"sku" valid:"stringlength(1|4)"`
+}
+
+// Extra is not validated, since the Extra field of User has no valid tag.
+type Extra struct {
+	Note gomu.String `json:"note"`
+}
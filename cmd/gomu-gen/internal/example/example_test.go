@@ -0,0 +1,71 @@
+package example
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	gomu.TypeTagMap.Set(reflect.TypeOf(gomu.Int{}), "even", func(i interface{}, params ...string) bool {
+		return i.(int64)%2 == 0
+	})
+}
+
+func validUser() User {
+	return User{
+		Base:     Base{ID: gomu.IntFrom(1)},
+		Name:     gomu.StringFrom("foo"),
+		Nickname: gomu.StringFrom("f"),
+		Homepage: gomu.StringFrom("https://example.com/"),
+		Code:     gomu.StringFrom("abcd"),
+		Age:      gomu.IntFrom(20),
+		Rank:     gomu.IntFrom(2),
+		Agreed:   gomu.BoolFrom(true),
+		Born:     gomu.TimeFrom(time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)),
+		Ratio:    gomu.FloatFrom(0.5),
+		Score:    gomu.NullableFrom(1),
+		Even:     gomu.IntFrom(2),
+		Count:    1,
+		Address:  &Address{Zip: gomu.StringFrom("123")},
+		Items:    []Item{{SKU: gomu.StringFrom("a")}},
+		Extra:    &Extra{Note: gomu.StringFrom("x")},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := validUser()
+	invalid.ID = gomu.IntFrom(-1)
+	invalid.Name = gomu.StringFrom("12345678901")
+	invalid.Nickname = gomu.NewString("", true, true)
+	invalid.Homepage = gomu.StringFrom("example")
+	invalid.Code = gomu.StringFrom("abc")
+	invalid.Age = gomu.IntFrom(200)
+	invalid.Rank = gomu.IntFrom(5)
+	invalid.Agreed = gomu.BoolFrom(false)
+	invalid.Born = gomu.TimeFrom(time.Now().Add(time.Hour))
+	invalid.Ratio = gomu.NewFloat(0, true, true)
+	invalid.Even = gomu.IntFrom(3)
+	invalid.Address = &Address{Zip: gomu.StringFrom("123456789")}
+	invalid.Items = []Item{{SKU: gomu.StringFrom("a")}, {SKU: gomu.StringFrom("abcde")}}
+
+	omitted := validUser()
+	omitted.Homepage = gomu.String{}
+	omitted.Address = nil
+
+	for name, user := range map[string]User{
+		"valid":   validUser(),
+		"invalid": invalid,
+		"omitted": omitted,
+		"zero":    {},
+	} {
+		_, expect := gomu.Validate(user)
+		target := user.Validate()
+		assert.Equal(t, expect, target, "Validate(%s) fail", name)
+	}
+	assert.Nil(t, validUser().Validate(), "Validate(valid) fail")
+	assert.Len(t, invalid.Validate(), 13, "Validate(invalid) fail")
+}
@@ -0,0 +1,131 @@
+// Code generated by gomu-gen; DO NOT EDIT.
+
+package example
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hapoon/gomu"
+)
+
+// Validate validates t by its valid tags as gomu.Validate does, and returns gomu.Errors if it is not valid.
+func (t Address) Validate() error {
+	if errs := t.gomuValidate(""); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (t Address) gomuValidate(path string) (errs gomu.Errors) {
+	switch v := t.Zip; {
+	case v.Null || !v.Valid:
+		errs = append(errs, gomu.Error{Name: "Zip", Path: gomu.JoinPath(path, "zip"), Validator: "required", Err: errors.New("non zero value required"), CustomErrorMessageExists: true})
+	case !gomu.StringLength(v.String, "1", "8"):
+		errs = append(errs, gomu.Error{Name: "Zip", Path: gomu.JoinPath(path, "zip"), Validator: "stringlength", Params: []string{"1", "8"}, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.String), "stringlength(1|8)")})
+	}
+	return
+}
+
+// Validate validates t by its valid tags as gomu.Validate does, and returns gomu.Errors if it is not valid.
+func (t Base) Validate() error {
+	if errs := t.gomuValidate(""); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (t Base) gomuValidate(path string) (errs gomu.Errors) {
+	switch v := t.ID; {
+	case v.Null || !v.Valid:
+		errs = append(errs, gomu.Error{Name: "ID", Path: gomu.JoinPath(path, "id"), Validator: "required", Err: errors.New("non zero value required"), CustomErrorMessageExists: true})
+	case !gomu.IsPositive(v.Int64):
+		errs = append(errs, gomu.Error{Name: "ID", Path: gomu.JoinPath(path, "id"), Validator: "positive", Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.Int64), "positive")})
+	}
+	return
+}
+
+// Validate validates t by its valid tags as gomu.Validate does, and returns gomu.Errors if it is not valid.
+func (t Item) Validate() error {
+	if errs := t.gomuValidate(""); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (t Item) gomuValidate(path string) (errs gomu.Errors) {
+	switch v := t.SKU; {
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case !gomu.StringLength(v.String, "1", "4"):
+		errs = append(errs, gomu.Error{Name: "SKU", Path: gomu.JoinPath(path, "sku"), Validator: "stringlength", Params: []string{"1", "4"}, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.String), "stringlength(1|4)")})
+	}
+	return
+}
+
+// Validate validates t by its valid tags as gomu.Validate does, and returns gomu.Errors if it is not valid.
+func (t User) Validate() error {
+	if errs := t.gomuValidate(""); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (t User) gomuValidate(path string) (errs gomu.Errors) {
+	errs = append(errs, gomu.CheckTag(&t.Base, t, "Base", "nested", path)...)
+	switch v := t.Name; {
+	case v.Null || !v.Valid:
+		errs = append(errs, gomu.Error{Name: "Name", Path: gomu.JoinPath(path, "name"), Validator: "required", Err: errors.New("non zero value required"), CustomErrorMessageExists: true})
+	case !gomu.StringLength(v.String, "1", "10"):
+		errs = append(errs, gomu.Error{Name: "Name", Path: gomu.JoinPath(path, "name"), Validator: "stringlength", Params: []string{"1", "10"}, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.String), "stringlength(1|10)")})
+	}
+	switch v := t.Nickname; {
+	case !v.Valid:
+		errs = append(errs, gomu.Error{Name: "Nickname", Path: gomu.JoinPath(path, "nickname"), Validator: "present", Err: errors.New("value must be present")})
+	case v.Valid && v.Null:
+		errs = append(errs, gomu.Error{Name: "Nickname", Path: gomu.JoinPath(path, "nickname"), Validator: "notnull", Err: errors.New("nickname must not be null"), CustomErrorMessageExists: true})
+	}
+	switch v := t.Homepage; {
+	case !v.Valid: // omitunassigned
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case !gomu.IsRequestURL(v.String):
+		errs = append(errs, gomu.Error{Name: "Homepage", Path: gomu.JoinPath(path, "homepage"), Validator: "requrl", Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.String), "requrl")})
+	}
+	switch v := t.Code; {
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case gomu.ByteLength(v.String, "3", "3"):
+		errs = append(errs, gomu.Error{Name: "Code", Path: gomu.JoinPath(path, "code"), Validator: "!length", Params: []string{"3", "3"}, Err: fmt.Errorf("%s does validate as %s", fmt.Sprint(v.String), "length(3|3)")})
+	}
+	switch v := t.Age; {
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case !gomu.IntRange(v.Int64, "0", "150"):
+		errs = append(errs, gomu.Error{Name: "Age", Path: gomu.JoinPath(path, "age"), Validator: "range", Params: []string{"0", "150"}, Err: errors.New("age is out of range"), CustomErrorMessageExists: true})
+	}
+	switch v := t.Rank; {
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case !gomu.IntIn(v.Int64, "1", "2", "3"):
+		errs = append(errs, gomu.Error{Name: "Rank", Path: gomu.JoinPath(path, "rank"), Validator: "in", Params: []string{"1", "2", "3"}, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.Int64), "in(1|2|3)")})
+	}
+	switch v := t.Agreed; {
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case !gomu.BoolEq(v.Bool, "true"):
+		errs = append(errs, gomu.Error{Name: "Agreed", Path: gomu.JoinPath(path, "agreed"), Validator: "eq", Params: []string{"true"}, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.Bool), "eq(true)")})
+	}
+	switch v := t.Born; {
+	case v.Null || !v.Valid: // the validators only apply to non-null values
+	case !gomu.IsPast(v.Time):
+		errs = append(errs, gomu.Error{Name: "Born", Path: gomu.JoinPath(path, "born"), Validator: "past", Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v.Time), "past")})
+	}
+	switch v := t.Ratio; {
+	case v.Valid && v.Null:
+		errs = append(errs, gomu.Error{Name: "Ratio", Path: gomu.JoinPath(path, "ratio"), Validator: "notnull", Err: errors.New("value must not be null")})
+	}
+	switch v := t.Score; {
+	case v.Null || !v.Valid:
+		errs = append(errs, gomu.Error{Name: "Score", Path: gomu.JoinPath(path, "score"), Validator: "required", Err: errors.New("non zero value required"), CustomErrorMessageExists: true})
+	}
+	errs = append(errs, gomu.CheckTag(&t.Even, t, "Even", "even", gomu.JoinPath(path, "even"))...)
+	errs = append(errs, gomu.CheckTag(&t.Count, t, "Count", "required", gomu.JoinPath(path, "count"))...)
+	errs = append(errs, gomu.CheckTag(&t.Address, t, "Address", "nested", gomu.JoinPath(path, "address"))...)
+	errs = append(errs, gomu.CheckTag(&t.Items, t, "Items", "nested", gomu.JoinPath(path, "items"))...)
+	return
+}
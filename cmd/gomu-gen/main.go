@@ -0,0 +1,53 @@
+// Command gomu-gen generates a Validate method for each struct type of a package with valid tags,
+// which checks the rules as straight-line code instead of parsing the tags by reflection on every call.
+// The methods return the same errors as gomu.Validate, as gomu.Errors, or nil.
+//
+// Usage:
+//
+//	//go:generate go run github.com/hapoon/gomu/cmd/gomu-gen [-type T1,T2] [-o gomu_validate.go]
+//
+// By default, methods are generated for every struct type of the package that has valid tags,
+// directly or in the struct types of the package it nests.
+//
+// The built-in validators of gomu types and the present, notnull, omitunassigned and required options
+// are compiled. Other tags, such as validators added to CustomTypeTagMap or TypeTagMap and tags on
+// types that are not gomu types, are checked at run time by gomu.CheckTag, and nested structs without
+// a generated method by gomu.ValidateAt. Overriding a built-in validator at run time has no effect
+// on the generated methods. When a value fails several validators of a tag, the error is for the first one
+// in the tag, as gomu.Validate reports it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of type names (default all struct types with valid tags)")
+	output := flag.String("o", "gomu_validate.go", "output file name, relative to the package directory")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+	if err := run(dir, filepath.Join(dir, *output), names); err != nil {
+		fmt.Fprintln(os.Stderr, "gomu-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string, names []string) error {
+	src, err := generate(dir, output, names)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
@@ -0,0 +1,68 @@
+// Command gomu-openapi writes the OpenAPI 3.1 components.schemas of the exported structs
+// in the given packages, which use gomu types and valid tags.
+//
+// Usage:
+//
+//	gomu-openapi [-o openapi.json] [-types CreateUserRequest,User] [-title API] [-version 1.0.0] [packages]
+//
+// The packages default to the package in the current directory.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hapoon/gomu/openapi"
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	output := flag.String("o", "", "output file (default standard output)")
+	typeNames := flag.String("types", "", "comma-separated list of type names (default all exported structs)")
+	title := flag.String("title", "API", "info.title of the document")
+	version := flag.String("version", "0.0.0", "info.version of the document")
+	flag.Parse()
+
+	if err := run(*output, *typeNames, *title, *version, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "gomu-openapi:", err)
+		os.Exit(1)
+	}
+}
+
+func run(output, typeNames, title, version string, patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedTypes}, patterns...)
+	if err != nil {
+		return err
+	}
+	var names []string
+	if typeNames != "" {
+		names = strings.Split(typeNames, ",")
+	}
+	components, err := openapi.FromPackages(pkgs, names...)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(openapi.Document{
+		OpenAPI:    openapi.Version,
+		Info:       openapi.Info{Title: title, Version: version},
+		Components: components,
+	})
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(output, buf.Bytes(), 0644)
+}
@@ -1,6 +1,9 @@
 package gomu
 
-import "strconv"
+import (
+	"strconv"
+	"time"
+)
 
 // ToInt convert the input string to an integer, or 0 if the input is not an integer.
 func ToInt(str string) (result int64, err error) {
@@ -10,3 +13,11 @@ func ToInt(str string) (result int64, err error) {
 	}
 	return
 }
+
+// ToTime convert the input string formatted as RFC 3339 or "2006-01-02" to a time.
+func ToTime(str string) (result time.Time, err error) {
+	if result, err = time.Parse(time.RFC3339, str); err == nil {
+		return
+	}
+	return time.Parse("2006-01-02", str)
+}
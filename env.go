@@ -0,0 +1,96 @@
+package gomu
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// LoadEnv fills the struct pointed to by dst from environment variables whose names start with prefix,
+// and validates it. See EnvLoader for the rules.
+func LoadEnv(dst interface{}, prefix string) error {
+	return (&EnvLoader{Prefix: prefix}).Load(dst)
+}
+
+// EnvLoader fills structs from environment variables.
+//
+// A field is filled from the variable named by its `env` tag with Prefix prepended.
+// A gomu field whose variable is unset is left unassigned (Valid is false),
+// one set to "" or "null" is set to Null, and any other value is decoded with UnmarshalText.
+// The `default` tag is decoded the same way into fields that are still unassigned.
+// A nested struct is filled with its own `env` tag and "_" appended to the prefix, if it has the tag.
+type EnvLoader struct {
+	// Prefix is prepended to every variable name.
+	Prefix string
+	// LookupEnv looks up a variable. os.LookupEnv is used if it is nil.
+	LookupEnv func(key string) (string, bool)
+}
+
+// Load fills the struct pointed to by dst and validates it with Validate.
+func (l *EnvLoader) Load(dst interface{}) error {
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gomu: LoadEnv requires a non-nil pointer to a struct; got %T", dst)
+	}
+	lookup := l.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	if err := loadEnvStruct(val.Elem(), l.Prefix, lookup); err != nil {
+		return err
+	}
+	if ok, err := Validate(dst); !ok {
+		if err == nil {
+			err = errors.New("gomu: config did not validate")
+		}
+		return err
+	}
+	return nil
+}
+
+func loadEnvStruct(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		name := sf.Tag.Get("env")
+		if name == "-" {
+			continue
+		}
+		if !isTextType(sf.Type) && sf.Type.Kind() == reflect.Struct {
+			nested := prefix
+			if name != "" {
+				nested = prefix + name + "_"
+			}
+			if err := loadEnvStruct(fv, nested, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" || !isTextType(sf.Type) {
+			continue
+		}
+		key := prefix + name
+		if str, ok := lookup(key); ok {
+			if err := decodeText(fv, str, key); err != nil {
+				return err
+			}
+			continue
+		}
+		def, ok := sf.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		if _, isGomu := fv.Interface().(tristater); (isGomu && !IsUnassigned(fv)) || (!isGomu && !fv.IsZero()) {
+			continue
+		}
+		if err := decodeText(fv, def, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
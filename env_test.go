@@ -0,0 +1,90 @@
+package gomu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructEnvDB struct {
+	Host String `env:"HOST" default:"localhost"`
+	Port Int    `env:"PORT" default:"5432" valid:"range(1|65535)"`
+}
+
+type testStructEnv struct {
+	Name    String          `env:"NAME" valid:"required"`
+	Debug   Bool            `env:"DEBUG"`
+	Timeout Int             `env:"TIMEOUT" default:"30"`
+	Ratio   Float           `env:"RATIO"`
+	Workers int             `env:"WORKERS" default:"4"`
+	DB      testStructEnvDB `env:"DB"`
+	Ignored String
+}
+
+func testLookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestEnvLoader(t *testing.T) {
+	l := &EnvLoader{
+		Prefix: "APP_",
+		LookupEnv: testLookupEnv(map[string]string{
+			"APP_NAME":    "test",
+			"APP_DEBUG":   "",
+			"APP_RATIO":   "null",
+			"APP_DB_PORT": "6543",
+			"APP_IGNORED": "x",
+		}),
+	}
+	var target testStructEnv
+	err := l.Load(&target)
+	checkError(err)
+	expect := testStructEnv{
+		Name:    StringFrom("test"),
+		Debug:   BoolFromPtr(nil),
+		Timeout: IntFrom(30),
+		Ratio:   FloatFromPtr(nil),
+		Workers: 4,
+		DB: testStructEnvDB{
+			Host: StringFrom("localhost"),
+			Port: IntFrom(6543),
+		},
+	}
+	assert.Equal(t, expect, target, "Load() fail")
+	// default is not applied to assigned fields
+	target = testStructEnv{Timeout: IntFrom(10), Workers: 8}
+	err = l.Load(&target)
+	checkError(err)
+	assert.Equal(t, IntFrom(10), target.Timeout, "Load(assigned) fail")
+	assert.Equal(t, 8, target.Workers, "Load(assigned) fail")
+}
+
+func TestEnvLoaderErrors(t *testing.T) {
+	// validation error
+	l := &EnvLoader{LookupEnv: testLookupEnv(map[string]string{"DB_PORT": "70000"})}
+	var target testStructEnv
+	err := l.Load(&target)
+	if assert.Error(t, err, "Load(invalid) fail") {
+		assert.IsType(t, Errors{}, err, "Load(invalid) fail")
+	}
+	// decode error
+	l = &EnvLoader{LookupEnv: testLookupEnv(map[string]string{"NAME": "test", "TIMEOUT": "abc"})}
+	target = testStructEnv{}
+	err = l.Load(&target)
+	assert.Error(t, err, "Load(decode error) fail")
+	// not a pointer
+	err = l.Load(target)
+	assert.Error(t, err, "Load(not pointer) fail")
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("GOMU_TEST_NAME", "test")
+	var target testStructEnv
+	err := LoadEnv(&target, "GOMU_TEST_")
+	checkError(err)
+	assert.Equal(t, StringFrom("test"), target.Name, "LoadEnv() fail")
+	assert.False(t, target.Debug.Valid, "LoadEnv() fail")
+}
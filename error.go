@@ -1,10 +1,21 @@
 package gomu
 
+import "encoding/json"
+
 // Error encapsulates a name, an error and whether there is a custom error message or not.
+// Path is the location of the field from the validated struct, such as "address.zip" or "items[2].sku",
+// which uses the json tag name if there is one.
+// Validator and Params are the validator of the tag that failed and its parameters.
+// For ValidateJSON, Path is a JSON Pointer such as "/address/zip" or "/items/2/sku",
+// and Offset is the byte offset of the value in the input, or of the end of the object missing the key.
 type Error struct {
 	Name                     string
+	Path                     string
+	Validator                string
+	Params                   []string
 	Err                      error
 	CustomErrorMessageExists bool
+	Offset                   int64
 }
 
 func (e Error) Error() string {
@@ -14,6 +25,27 @@ func (e Error) Error() string {
 	return e.Name + ": " + e.Err.Error()
 }
 
+// MarshalJSON implements json.Marshaler.
+func (e Error) MarshalJSON() ([]byte, error) {
+	path := e.Path
+	if path == "" {
+		path = e.Name
+	}
+	return json.Marshal(struct {
+		Path      string   `json:"path"`
+		Validator string   `json:"validator,omitempty"`
+		Params    []string `json:"params,omitempty"`
+		Message   string   `json:"message"`
+		Offset    int64    `json:"offset,omitempty"`
+	}{
+		Path:      path,
+		Validator: e.Validator,
+		Params:    e.Params,
+		Message:   e.Err.Error(),
+		Offset:    e.Offset,
+	})
+}
+
 // Errors is an array of multiple errors and conforms to the error interface.
 type Errors []error
 
@@ -28,3 +60,31 @@ func (e Errors) Error() (errStr string) {
 	}
 	return
 }
+
+// MarshalJSON implements json.Marshaler.
+// Each error is encoded as an object with its path, validator, params and message.
+func (e Errors) MarshalJSON() ([]byte, error) {
+	list := make([]interface{}, 0, len(e))
+	for _, err := range e {
+		switch x := err.(type) {
+		case json.Marshaler:
+			list = append(list, x)
+		default:
+			list = append(list, struct {
+				Message string `json:"message"`
+			}{err.Error()})
+		}
+	}
+	return json.Marshal(list)
+}
+
+// appendErrors appends err to errs, flattening it if it is Errors.
+func appendErrors(errs Errors, err error) Errors {
+	if nested, ok := err.(Errors); ok {
+		for _, e := range nested {
+			errs = appendErrors(errs, e)
+		}
+		return errs
+	}
+	return append(errs, err)
+}
@@ -1,6 +1,7 @@
 package gomu
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -35,3 +36,19 @@ func TestError(t *testing.T) {
 		assert.Equal(t, test.expected, actual, "Expected Error() to return '%v', got '%v'", test.expected, actual)
 	}
 }
+
+func TestErrorsMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	errs := Errors{
+		Error{Name: "Zip", Path: "address.zip", Validator: "stringlength", Params: []string{"1", "8"}, Err: fmt.Errorf("123456789 does not validate as stringlength(1|8)")},
+		&Error{Name: "Name", Validator: "required", Err: fmt.Errorf("non zero value required"), CustomErrorMessageExists: true},
+		fmt.Errorf("Error 1"),
+	}
+	expected := `[{"path":"address.zip","validator":"stringlength","params":["1","8"],"message":"123456789 does not validate as stringlength(1|8)"},` +
+		`{"path":"Name","validator":"required","message":"non zero value required"},` +
+		`{"message":"Error 1"}]`
+	actual, err := json.Marshal(errs)
+	checkError(err)
+	assert.Equal(t, expected, string(actual), "Expected MarshalJSON() to return '%v', got '%v'", expected, string(actual))
+}
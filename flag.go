@@ -0,0 +1,110 @@
+package gomu
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Flag returns a flag.Getter that sets this String.
+// String cannot implement flag.Value itself, because its String field takes the name of the String method.
+// Set with "" or "null" sets this String to null.
+func (s *String) Flag() flag.Getter {
+	return stringFlag{s}
+}
+
+type stringFlag struct {
+	s *String
+}
+
+func (f stringFlag) Set(str string) error {
+	return f.s.updateNullable(func(n *Nullable[string]) error { return n.Set(str) })
+}
+
+func (f stringFlag) String() string {
+	if f.s == nil {
+		return ""
+	}
+	return f.s.Nullable().String()
+}
+
+func (f stringFlag) Get() interface{} {
+	return f.s.Nullable().Get()
+}
+
+// Type returns the name of the value type, as used in the usage message of github.com/spf13/pflag.
+func (f stringFlag) Type() string {
+	return "string"
+}
+
+// RegisterFlags defines a flag on fs for every gomu field of the struct pointed to by dst
+// that has a `flag:"name,usage"` tag. A flag that is not passed leaves its field unassigned
+// (Valid is false), and one passed as "" or "null" sets it to Null.
+// A nested struct registers its fields with its own `flag` tag and "." prepended to their names,
+// if it has the tag.
+//
+// Since flag values only need Set and String, the same fields can be registered
+// on a github.com/spf13/pflag FlagSet with VarFlags.
+func RegisterFlags(fs *flag.FlagSet, dst interface{}) error {
+	return VarFlags(fs.Var, dst)
+}
+
+// VarFlags calls define for every field that RegisterFlags would register, with the value of the field.
+// The values also implement the Type method of github.com/spf13/pflag's Value,
+// so that a pflag FlagSet can be populated with:
+//
+//	gomu.VarFlags(func(v flag.Value, name, usage string) { fs.Var(v.(pflag.Value), name, usage) }, &cfg)
+func VarFlags(define func(value flag.Value, name, usage string), dst interface{}) error {
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gomu: RegisterFlags requires a non-nil pointer to a struct; got %T", dst)
+	}
+	return varFlagsStruct(define, val.Elem(), "")
+}
+
+func varFlagsStruct(define func(flag.Value, string, string), v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		name, usage := parseFlagTag(sf.Tag.Get("flag"))
+		if name == "-" {
+			continue
+		}
+		if sf.Type.Kind() == reflect.Struct && !sf.Type.Implements(tristaterType) {
+			nested := prefix
+			if name != "" {
+				nested = prefix + name + "."
+			}
+			if err := varFlagsStruct(define, fv, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" || !sf.Type.Implements(tristaterType) {
+			continue
+		}
+		var value flag.Value
+		switch p := fv.Addr().Interface().(type) {
+		case *String:
+			value = p.Flag()
+		case flag.Value:
+			value = p
+		default:
+			return fmt.Errorf("gomu: field %s of type %s cannot be a flag", sf.Name, sf.Type)
+		}
+		define(value, prefix+name, usage)
+	}
+	return nil
+}
+
+func parseFlagTag(tag string) (name string, usage string) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
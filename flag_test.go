@@ -0,0 +1,88 @@
+package gomu
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructFlagDB struct {
+	Host String `flag:"host,database host"`
+	Port Int    `flag:"port,database port"`
+}
+
+type testStructFlag struct {
+	Name    String           `flag:"name,name of the service"`
+	Debug   Bool             `flag:"debug,enable debug output"`
+	Ratio   Float            `flag:"ratio"`
+	Start   Time             `flag:"start"`
+	Retries Nullable[int]    `flag:"retries"`
+	DB      testStructFlagDB `flag:"db"`
+	Ignored String
+}
+
+func TestRegisterFlags(t *testing.T) {
+	var cfg testStructFlag
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	err := RegisterFlags(fs, &cfg)
+	checkError(err)
+
+	err = fs.Parse([]string{"-name", "svc", "-debug", "-ratio=null", "-start", "2020-01-02T03:04:05Z", "-db.port", "5432"})
+	checkError(err)
+	assert.Equal(t, StringFrom("svc"), cfg.Name, "Name fail")
+	assert.Equal(t, BoolFrom(true), cfg.Debug, "Debug fail")
+	assert.Equal(t, NewFloat(0, true, true), cfg.Ratio, "Ratio fail")
+	assert.Equal(t, TimeFrom(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), cfg.Start, "Start fail")
+	assert.False(t, cfg.Retries.Valid, "Retries fail")
+	assert.False(t, cfg.DB.Host.Valid, "DB.Host fail")
+	assert.Equal(t, IntFrom(5432), cfg.DB.Port, "DB.Port fail")
+	assert.Nil(t, fs.Lookup("Ignored"), "Ignored fail")
+	assert.Equal(t, "database host", fs.Lookup("db.host").Usage, "Usage fail")
+
+	err = fs.Parse([]string{"-db.port", "x"})
+	assert.Error(t, err, "invalid value fail")
+
+	err = RegisterFlags(fs, cfg)
+	assert.Error(t, err, "non-pointer fail")
+}
+
+func TestFlagValue(t *testing.T) {
+	var s String
+	err := s.Flag().Set("")
+	checkError(err)
+	assert.Equal(t, NewString("", true, true), s, "String Set null fail")
+	err = s.Flag().Set("a")
+	checkError(err)
+	assert.Equal(t, "a", s.Flag().String(), "String String fail")
+	assert.Equal(t, "a", s.Flag().Get(), "String Get fail")
+
+	var i Int
+	assert.Equal(t, "", i.String(), "Int unassigned String fail")
+	assert.Nil(t, i.Get(), "Int unassigned Get fail")
+	err = i.Set("12")
+	checkError(err)
+	assert.Equal(t, "12", i.String(), "Int String fail")
+	assert.Equal(t, int64(12), i.Get(), "Int Get fail")
+	assert.Equal(t, "int", i.Type(), "Int Type fail")
+	err = i.Set("null")
+	checkError(err)
+	assert.Equal(t, NewInt(0, true, true), i, "Int Set null fail")
+
+	var b Bool
+	assert.True(t, b.IsBoolFlag(), "IsBoolFlag fail")
+	err = b.Set("1")
+	checkError(err)
+	assert.Equal(t, BoolFrom(true), b, "Bool Set fail")
+	err = b.Set("x")
+	assert.Error(t, err, "Bool Set error fail")
+	assert.False(t, b.Valid, "Bool Set error Valid fail")
+
+	n := NullableFrom(1.5)
+	assert.Equal(t, "1.5", n.String(), "Nullable String fail")
+	assert.Equal(t, 1.5, n.Get(), "Nullable Get fail")
+	assert.Equal(t, "float64", n.Type(), "Nullable Type fail")
+}
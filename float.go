@@ -0,0 +1,207 @@
+package gomu
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Float is a nullable float64.
+// If key is assigned and value is not null, Float is specified value, Null is false, Valid is true.
+// If value is null, Null is true.
+// If key is not assigned, Valid is false.
+type Float struct {
+	Float64 float64
+	Null    bool
+	Valid   bool
+}
+
+// NewFloat creates a new Float.
+func NewFloat(f float64, n bool, valid bool) Float {
+	return Float{
+		Float64: f,
+		Null:    n,
+		Valid:   valid,
+	}
+}
+
+// FloatFrom creates a new Float that will always be valid.
+func FloatFrom(f float64) Float {
+	return NewFloat(f, false, true)
+}
+
+// FloatFromPtr creates a new Float that will be null if f is nil.
+func FloatFromPtr(f *float64) Float {
+	if f == nil {
+		return NewFloat(0, true, true)
+	}
+	return NewFloat(*f, false, true)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Float) UnmarshalJSON(data []byte) (err error) {
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return
+	}
+	switch x := v.(type) {
+	case float64:
+		f.Float64 = x
+	case nil:
+		f.Null = true
+	default:
+		err = fmt.Errorf("json: cannot unmarshal %v into Go value of type gomu.Float", reflect.TypeOf(v).Name())
+	}
+	f.Valid = err == nil
+	return
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *Float) UnmarshalText(text []byte) (err error) {
+	if text == nil {
+		return
+	}
+	str := string(text)
+	if str == "" || str == "null" {
+		f.Null = true
+		f.Valid = true
+		return
+	}
+	f.Float64, err = strconv.ParseFloat(str, 64)
+	f.Valid = err == nil
+	return
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f Float) MarshalJSON() ([]byte, error) {
+	if f.Null || !f.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.Float64)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f Float) MarshalText() ([]byte, error) {
+	if !f.Valid {
+		return nil, nil
+	}
+	if f.Null {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatFloat(f.Float64, 'f', -1, 64)), nil
+}
+
+// SetValid changes this Float value and also sets Valid to be true.
+func (f *Float) SetValid(n float64) {
+	f.Float64 = n
+	f.Null = false
+	f.Valid = true
+}
+
+// Ptr returns a pointer to this Float's value, or a nil pointer if this Float is null or not valid.
+func (f Float) Ptr() *float64 {
+	if f.Null || !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+// Scan implements database/sql.Scanner.
+func (f *Float) Scan(value interface{}) (err error) {
+	switch x := value.(type) {
+	case float64:
+		f.Float64 = x
+	case float32:
+		f.Float64 = float64(x)
+	case int64:
+		f.Float64 = float64(x)
+	case []byte:
+		f.Float64, err = strconv.ParseFloat(string(x), 64)
+	case string:
+		f.Float64, err = strconv.ParseFloat(x, 64)
+	case nil:
+		f.Null = true
+	default:
+		err = fmt.Errorf("gomu: cannot scan type %T into gomu.Float: %v", value, value)
+	}
+	f.Valid = err == nil
+	return
+}
+
+// Value implements database/sql.Valuer.
+func (f Float) Value() (driver.Value, error) {
+	if !f.Valid || f.Null {
+		return nil, nil
+	}
+	return f.Float64, nil
+}
+
+// Set implements flag.Value. "" and "null" set Float to null.
+func (f *Float) Set(s string) error {
+	return f.updateNullable(func(n *Nullable[float64]) error { return n.Set(s) })
+}
+
+// String implements flag.Value.
+func (f Float) String() string {
+	return f.Nullable().String()
+}
+
+// Get implements flag.Getter. It returns nil if this Float is null or not valid.
+func (f Float) Get() interface{} {
+	return f.Nullable().Get()
+}
+
+// Type returns the name of the value type, as used in the usage message of github.com/spf13/pflag.
+func (f Float) Type() string {
+	return "float64"
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+// It will encode null if this Float is null or not valid.
+func (f Float) MarshalYAML() (interface{}, error) {
+	return f.Nullable().MarshalYAML()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also accepts.
+// Note that both packages leave Float unassigned for null instead of calling this method;
+// use github.com/hapoon/gomu/yaml to decode null as Null.
+func (f *Float) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return f.updateNullable(func(n *Nullable[float64]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// IsZero reports whether this Float is not valid, so that the omitempty option of gopkg.in/yaml.v3
+// and the omitzero option of encoding/json omit unassigned values.
+func (f Float) IsZero() bool {
+	return f.Nullable().IsZero()
+}
+
+// MarshalXML implements xml.Marshaler.
+// It will encode nothing if this Float is not valid, and an element with xsi:nil="true" if it is null.
+func (f Float) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return f.Nullable().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+// An element with xsi:nil="true" or without content sets Float to null.
+func (f *Float) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return f.updateNullable(func(n *Nullable[float64]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr.
+// It will encode no attribute if this Float is null or not valid.
+func (f Float) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return f.Nullable().MarshalXMLAttr(name)
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr. An empty attribute sets Float to null.
+func (f *Float) UnmarshalXMLAttr(attr xml.Attr) error {
+	return f.updateNullable(func(n *Nullable[float64]) error { return n.UnmarshalXMLAttr(attr) })
+}
+
+func (f Float) tristate() (null bool, valid bool) {
+	return f.Null, f.Valid
+}
@@ -0,0 +1,251 @@
+package gomu
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructFloat struct {
+	Price Float `json:"price"`
+}
+
+func TestFloatFrom(t *testing.T) {
+	// value > 0
+	target := FloatFrom(123.45)
+	expect := Float{
+		Float64: 123.45,
+		Null:    false,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, "FloatFrom(123.45) fail")
+	// value = 0
+	target = FloatFrom(0)
+	expect = Float{
+		Float64: 0,
+		Null:    false,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, "FloatFrom(0) fail")
+}
+
+func TestFloatFromPtr(t *testing.T) {
+	// float64 pointer
+	f := 123.45
+	target := FloatFromPtr(&f)
+	expect := Float{
+		Float64: 123.45,
+		Null:    false,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, "FloatFromPtr() fail")
+	// nil
+	target = FloatFromPtr(nil)
+	expect = Float{
+		Float64: 0,
+		Null:    true,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, "FloatFromPtr(nil) fail")
+}
+
+func TestUnmarshalJSONFloat(t *testing.T) {
+	// key and value assigned
+	j := []byte(`{"price":123.45}`)
+	target := testStructFloat{}
+	json.Unmarshal(j, &target)
+	expect := testStructFloat{
+		Price: Float{
+			Float64: 123.45,
+			Null:    false,
+			Valid:   true,
+		},
+	}
+	assert.Equal(t, target, expect, "UnmarshalJSON(float) fail")
+	// value is null
+	j = []byte(`{"price":null}`)
+	target = testStructFloat{}
+	json.Unmarshal(j, &target)
+	expect = testStructFloat{
+		Price: Float{
+			Float64: 0,
+			Null:    true,
+			Valid:   true,
+		},
+	}
+	assert.Equal(t, target, expect, "UnmarshalJSON(null) fail")
+	// key is not assigned
+	j = []byte("{}")
+	target = testStructFloat{}
+	json.Unmarshal(j, &target)
+	expect = testStructFloat{
+		Price: Float{
+			Float64: 0,
+			Null:    false,
+			Valid:   false,
+		},
+	}
+	assert.Equal(t, target, expect, "UnmarshalJSON(key is not assigned) fail")
+	// key and value assigned(value is string)
+	j = []byte(`{"price":"123.45"}`)
+	target = testStructFloat{}
+	json.Unmarshal(j, &target)
+	expect = testStructFloat{
+		Price: Float{
+			Float64: 0,
+			Null:    false,
+			Valid:   false,
+		},
+	}
+	assert.Equal(t, target, expect, "UnmarshalJSON(string) fail")
+}
+
+func TestUnmarshalTextFloat(t *testing.T) {
+	// key and value assigned
+	target := Float{}
+	err := target.UnmarshalText([]byte("123.45"))
+	checkError(err)
+	expect := Float{
+		Float64: 123.45,
+		Null:    false,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, "UnmarshalText() fail")
+	// value is "null"
+	target = Float{}
+	err = target.UnmarshalText([]byte("null"))
+	checkError(err)
+	expect = Float{
+		Float64: 0,
+		Null:    true,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, `UnmarshalText("null") fail`)
+	// value is nil
+	target = Float{}
+	err = target.UnmarshalText(nil)
+	checkError(err)
+	expect = Float{
+		Float64: 0,
+		Null:    false,
+		Valid:   false,
+	}
+	assert.Equal(t, target, expect, "UnmarshalText(nil) fail")
+	// value is not a number
+	target = Float{}
+	err = target.UnmarshalText([]byte("abc"))
+	assert.Error(t, err, "UnmarshalText(abc) fail")
+	assert.False(t, target.Valid, "UnmarshalText(abc) fail")
+}
+
+func TestMarshalJSONFloat(t *testing.T) {
+	// value assigned
+	ts := testStructFloat{
+		Price: FloatFrom(123.45),
+	}
+	expect := []byte(`{"price":123.45}`)
+	target, err := json.Marshal(ts)
+	checkError(err)
+	assert.Equal(t, target, expect, "MarshalJSON(123.45) fail")
+	// value is null
+	ts = testStructFloat{
+		Price: FloatFromPtr(nil),
+	}
+	expect = []byte(`{"price":null}`)
+	target, err = json.Marshal(ts)
+	checkError(err)
+	assert.Equal(t, target, expect, "MarshalJSON(null) fail")
+	// key is not assigned
+	ts = testStructFloat{}
+	expect = []byte(`{"price":null}`)
+	target, err = json.Marshal(ts)
+	checkError(err)
+	assert.Equal(t, target, expect, "MarshalJSON(key is not assigned) fail")
+}
+
+func TestMarshalTextFloat(t *testing.T) {
+	// value assigned
+	f := FloatFrom(123.45)
+	target, err := f.MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte("123.45"), "MarshalText(123.45) fail")
+	// value is null
+	f = FloatFromPtr(nil)
+	target, err = f.MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte("null"), "MarshalText(null) fail")
+	// key is not assigned
+	f = Float{}
+	target, err = f.MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte(nil), "MarshalText(key is not assigned) fail")
+}
+
+func TestSetValidFloat(t *testing.T) {
+	target := FloatFromPtr(nil)
+	target.SetValid(123.45)
+	expect := Float{
+		Float64: 123.45,
+		Null:    false,
+		Valid:   true,
+	}
+	assert.Equal(t, target, expect, "SetValid(123.45) fail")
+}
+
+func TestPtrFloat(t *testing.T) {
+	// value assigned
+	f := FloatFrom(123.45)
+	ptr := f.Ptr()
+	assert.Equal(t, *ptr, 123.45, "Ptr() fail")
+	// value is null
+	f = FloatFromPtr(nil)
+	ptr = f.Ptr()
+	assert.Nil(t, ptr, "Ptr() null fail")
+	// not valid
+	f = Float{}
+	ptr = f.Ptr()
+	assert.Nil(t, ptr, "Ptr() not valid fail")
+}
+
+func TestScanFloat(t *testing.T) {
+	// float64
+	target := Float{}
+	err := target.Scan(123.45)
+	checkError(err)
+	assert.Equal(t, target, FloatFrom(123.45), "Scan(123.45) fail")
+	// []byte
+	target = Float{}
+	err = target.Scan([]byte("123.45"))
+	checkError(err)
+	assert.Equal(t, target, FloatFrom(123.45), `Scan([]byte("123.45")) fail`)
+	// nil
+	target = Float{}
+	err = target.Scan(nil)
+	checkError(err)
+	assert.Equal(t, target, FloatFromPtr(nil), "Scan(nil) fail")
+	// unsupported type
+	target = Float{}
+	err = target.Scan(true)
+	assert.Error(t, err, "Scan(true) fail")
+	assert.False(t, target.Valid, "Scan(true) fail")
+}
+
+func TestValueFloat(t *testing.T) {
+	// value assigned
+	f := FloatFrom(123.45)
+	data, err := f.Value()
+	checkError(err)
+	assert.Equal(t, data, driver.Value(123.45), "Value 123.45 fail")
+	// value is null
+	f = FloatFromPtr(nil)
+	data, err = f.Value()
+	checkError(err)
+	assert.Nil(t, data, "Value null fail")
+	// not assigned
+	f = Float{}
+	data, err = f.Value()
+	checkError(err)
+	assert.Nil(t, data, "Value not assigned fail")
+}
@@ -0,0 +1,26 @@
+package gomu
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// The functions in this file are used by the Validate methods that cmd/gomu-gen generates,
+// for the fields whose rules it cannot compile.
+
+// JoinPath returns the path of the field name in the struct at path, such as "address.zip".
+func JoinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// CheckTag validates *v against tag like Validate does for the field name of struct o with the valid tag tag.
+func CheckTag(v interface{}, o interface{}, name, tag, path string) Errors {
+	field := reflect.StructField{Name: name, Tag: reflect.StructTag(tagName + ":" + strconv.Quote(tag))}
+	if _, err := typeCheck(reflect.ValueOf(v).Elem(), field, reflect.ValueOf(o), path); err != nil {
+		return appendErrors(nil, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hapoon/gomu"
+)
+
+// DefaultMaxBodySize is the body size limit of DefaultBinder.
+const DefaultMaxBodySize = 1 << 20
+
+// Binder decodes requests into structs of gomu types and validates them.
+type Binder struct {
+	// MaxBodySize is the maximum size of the request body in bytes. No limit if it is 0 or less.
+	MaxBodySize int64
+	// DisallowUnknownFields makes Bind fail when the body or the query has a key that does not match any field.
+	DisallowUnknownFields bool
+}
+
+// DefaultBinder is the Binder used by Bind and Handle.
+var DefaultBinder = &Binder{MaxBodySize: DefaultMaxBodySize}
+
+// DecodeError is returned by Bind when the request could not be decoded.
+type DecodeError struct {
+	// Status is the HTTP status code to respond with.
+	Status int
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return "httpx: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned by Bind when the decoded struct did not validate.
+type ValidationError struct {
+	// Err is the error returned by gomu.Validate, usually gomu.Errors.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return "httpx: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Bind decodes r into dst with DefaultBinder and validates it.
+func Bind(r *http.Request, dst interface{}) error {
+	return DefaultBinder.Bind(r, dst)
+}
+
+// Bind decodes r into dst, which must be a pointer to a struct, and validates it with gomu.Validate.
+// A JSON body is decoded for application/json, the form for application/x-www-form-urlencoded
+// and multipart/form-data, and the query for requests without a body, the latter two with gomu.DecodeValues.
+// It returns a *DecodeError or a *ValidationError on failure.
+func (b *Binder) Bind(r *http.Request, dst interface{}) error {
+	if err := b.decode(r, dst); err != nil {
+		var de *DecodeError
+		if errors.As(err, &de) {
+			return de
+		}
+		var me *http.MaxBytesError
+		if errors.As(err, &me) {
+			return &DecodeError{Status: http.StatusRequestEntityTooLarge, Err: err}
+		}
+		return &DecodeError{Status: http.StatusBadRequest, Err: err}
+	}
+	if ok, err := gomu.Validate(dst); !ok {
+		if err == nil {
+			err = gomu.Errors{}
+		}
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+func (b *Binder) decode(r *http.Request, dst interface{}) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return b.decodeValues(r.URL.Query(), dst)
+	}
+	if b.MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, b.MaxBodySize)
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return &DecodeError{Status: http.StatusUnsupportedMediaType, Err: err}
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		dec := json.NewDecoder(r.Body)
+		if b.DisallowUnknownFields {
+			dec.DisallowUnknownFields()
+		}
+		if err = dec.Decode(dst); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err = dec.Decode(&struct{}{}); err != io.EOF {
+			if err == nil {
+				err = errors.New("invalid JSON: data after the top-level value")
+			}
+			return err
+		}
+		return nil
+	case mediaType == "application/x-www-form-urlencoded":
+		if err = r.ParseForm(); err != nil {
+			return err
+		}
+		return b.decodeValues(r.Form, dst)
+	case mediaType == "multipart/form-data":
+		if err = r.ParseMultipartForm(b.MaxBodySize); err != nil {
+			return err
+		}
+		return b.decodeValues(r.Form, dst)
+	default:
+		return &DecodeError{Status: http.StatusUnsupportedMediaType, Err: fmt.Errorf("unsupported media type %q", mediaType)}
+	}
+}
+
+func (b *Binder) decodeValues(values url.Values, dst interface{}) error {
+	d := gomu.ValuesDecoder{DisallowUnknownKeys: b.DisallowUnknownFields}
+	return d.Decode(values, dst)
+}
+
+// Handle returns a handler that binds each request into a new T with DefaultBinder before calling fn.
+// A request that fails to decode is answered with a problem of the DecodeError's status,
+// and one that fails to validate with WriteValidationError.
+func Handle[T any](fn func(w http.ResponseWriter, r *http.Request, req *T)) http.Handler {
+	return HandleWith(DefaultBinder, fn)
+}
+
+// HandleWith is like Handle but binds requests with b.
+func HandleWith[T any](b *Binder, fn func(w http.ResponseWriter, r *http.Request, req *T)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(T)
+		if err := b.Bind(r, req); err != nil {
+			WriteError(w, err)
+			return
+		}
+		fn(w, r, req)
+	})
+}
+
+// WriteError writes the error returned by Bind to w as application/problem+json.
+func WriteError(w http.ResponseWriter, err error) error {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return WriteValidationError(w, ve.Err)
+	}
+	status := http.StatusBadRequest
+	var de *DecodeError
+	if errors.As(err, &de) {
+		status = de.Status
+		err = de.Err
+	}
+	return WriteProblem(w, Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	})
+}
@@ -0,0 +1,99 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructBind struct {
+	Name gomu.String `json:"name" valid:"required"`
+	Age  gomu.Int    `json:"age" form:"age"`
+}
+
+func TestBindJSON(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"test","age":null}`))
+	r.Header.Set("Content-Type", "application/json")
+	var target testStructBind
+	err := Bind(r, &target)
+	assert.NoError(t, err, "Bind(json) fail")
+	assert.Equal(t, testStructBind{Name: gomu.StringFrom("test"), Age: gomu.IntFromPtr(nil)}, target, "Bind(json) fail")
+}
+
+func TestBindForm(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=test&age=20"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var target testStructBind
+	err := Bind(r, &target)
+	assert.NoError(t, err, "Bind(form) fail")
+	assert.Equal(t, testStructBind{Name: gomu.StringFrom("test"), Age: gomu.IntFrom(20)}, target, "Bind(form) fail")
+}
+
+func TestBindQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?name=test", nil)
+	var target testStructBind
+	err := Bind(r, &target)
+	assert.NoError(t, err, "Bind(query) fail")
+	assert.Equal(t, testStructBind{Name: gomu.StringFrom("test")}, target, "Bind(query) fail")
+}
+
+func TestBindErrors(t *testing.T) {
+	binder := &Binder{MaxBodySize: 32, DisallowUnknownFields: true}
+	var tests = []struct {
+		body        string
+		contentType string
+		status      int
+	}{
+		{`{"name":`, "application/json", http.StatusBadRequest},
+		{`{"name":"test","unknown":1}`, "application/json", http.StatusBadRequest},
+		{`{"name":"a"} {"name":"b"}`, "application/json", http.StatusBadRequest},
+		{`{"name":"a"} garbage`, "application/json", http.StatusBadRequest},
+		{`{"name":"12345678901234567890123456789"}`, "application/json", http.StatusRequestEntityTooLarge},
+		{`name=test`, "text/plain", http.StatusUnsupportedMediaType},
+		{`name=test&age=abc`, "application/x-www-form-urlencoded", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+		r.Header.Set("Content-Type", test.contentType)
+		var target testStructBind
+		err := binder.Bind(r, &target)
+		var de *DecodeError
+		if assert.True(t, errors.As(err, &de), "Expected Bind(%q) to return DecodeError, got %v", test.body, err) {
+			assert.Equal(t, test.status, de.Status, "Expected Bind(%q) status to be %v, got %v", test.body, test.status, de.Status)
+		}
+	}
+	// validation error
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":1}`))
+	r.Header.Set("Content-Type", "application/json")
+	var target testStructBind
+	err := binder.Bind(r, &target)
+	var ve *ValidationError
+	if assert.True(t, errors.As(err, &ve), "Expected Bind() to return ValidationError, got %v", err) {
+		assert.IsType(t, gomu.Errors{}, ve.Err, "ValidationError.Err fail")
+	}
+}
+
+func TestHandle(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request, req *testStructBind) {
+		w.Write([]byte(req.Name.String))
+	})
+	// ok
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=test", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "Handle() status fail")
+	assert.Equal(t, "test", rec.Body.String(), "Handle() body fail")
+	// validation error
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, "Handle(invalid) status fail")
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"), "Handle(invalid) content type fail")
+	// decode error
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?age=abc", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Handle(decode error) status fail")
+}
@@ -0,0 +1,89 @@
+// Package httpx renders gomu validation errors as HTTP responses.
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hapoon/gomu"
+)
+
+// ContentType is the media type of problem details (RFC 7807).
+const ContentType = "application/problem+json"
+
+// Problem is a problem details object (RFC 7807) with the invalid-params extension.
+type Problem struct {
+	Type          string         `json:"type,omitempty"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is a field that failed validation.
+type InvalidParam struct {
+	Name      string   `json:"name"`
+	Reason    string   `json:"reason"`
+	Validator string   `json:"validator,omitempty"`
+	Params    []string `json:"params,omitempty"`
+}
+
+// NewProblem creates a Problem with status 422 from the error returned by gomu.Validate.
+// Each gomu.Error becomes an InvalidParam named after its path.
+func NewProblem(err error) Problem {
+	p := Problem{
+		Title:  "Your request parameters didn't validate.",
+		Status: http.StatusUnprocessableEntity,
+	}
+	var errs gomu.Errors
+	switch x := err.(type) {
+	case gomu.Errors:
+		errs = x
+	case nil:
+	default:
+		errs = gomu.Errors{x}
+	}
+	for _, e := range errs {
+		p.InvalidParams = append(p.InvalidParams, newInvalidParam(e))
+	}
+	return p
+}
+
+func newInvalidParam(err error) InvalidParam {
+	var e gomu.Error
+	switch x := err.(type) {
+	case gomu.Error:
+		e = x
+	case *gomu.Error:
+		e = *x
+	default:
+		return InvalidParam{Reason: err.Error()}
+	}
+	name := e.Path
+	if name == "" {
+		name = e.Name
+	}
+	return InvalidParam{
+		Name:      name,
+		Reason:    e.Err.Error(),
+		Validator: e.Validator,
+		Params:    e.Params,
+	}
+}
+
+// WriteProblem writes p to w as application/problem+json with the status of p.
+// A zero status is written as 500 Internal Server Error.
+func WriteProblem(w http.ResponseWriter, p Problem) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// WriteValidationError writes the error returned by gomu.Validate to w as application/problem+json with status 422.
+func WriteValidationError(w http.ResponseWriter, err error) error {
+	return WriteProblem(w, NewProblem(err))
+}
@@ -0,0 +1,59 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructAddress struct {
+	Zip gomu.String `json:"zip" valid:"stringlength(1|8)"`
+}
+
+type testStructRequest struct {
+	Name    gomu.String       `json:"name" valid:"required"`
+	Address testStructAddress `json:"address" valid:"nested"`
+}
+
+func TestNewProblem(t *testing.T) {
+	_, err := gomu.Validate(testStructRequest{Address: testStructAddress{Zip: gomu.StringFrom("123456789")}})
+	p := NewProblem(err)
+	expect := Problem{
+		Title:  "Your request parameters didn't validate.",
+		Status: http.StatusUnprocessableEntity,
+		InvalidParams: []InvalidParam{
+			{Name: "name", Reason: "non zero value required", Validator: "required"},
+			{Name: "address.zip", Reason: "123456789 does not validate as stringlength(1|8)", Validator: "stringlength", Params: []string{"1", "8"}},
+		},
+	}
+	assert.Equal(t, expect, p, "NewProblem() fail")
+	// not gomu.Errors
+	p = NewProblem(fmt.Errorf("function only accepts structs; got string"))
+	assert.Equal(t, []InvalidParam{{Reason: "function only accepts structs; got string"}}, p.InvalidParams, "NewProblem(error) fail")
+	// nil
+	p = NewProblem(nil)
+	assert.Nil(t, p.InvalidParams, "NewProblem(nil) fail")
+}
+
+func TestWriteValidationError(t *testing.T) {
+	_, err := gomu.Validate(testStructRequest{})
+	rec := httptest.NewRecorder()
+	err = WriteValidationError(rec, err)
+	assert.NoError(t, err, "WriteValidationError() fail")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, "WriteValidationError() status fail")
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"), "WriteValidationError() content type fail")
+	expect := `{"title":"Your request parameters didn't validate.","status":422,"invalid-params":[{"name":"name","reason":"non zero value required","validator":"required"}]}` + "\n"
+	assert.Equal(t, expect, rec.Body.String(), "WriteValidationError() body fail")
+}
+
+func TestWriteProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WriteProblem(rec, Problem{})
+	assert.NoError(t, err, "WriteProblem(zero) fail")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "WriteProblem(zero) status fail")
+	assert.Equal(t, `{"title":"","status":500}`+"\n", rec.Body.String(), "WriteProblem(zero) body fail")
+}
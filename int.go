@@ -1,7 +1,9 @@
 package gomu
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -106,17 +108,101 @@ func (i Int) Ptr() *int64 {
 
 // Scan implements database/sql.Scanner.
 func (i *Int) Scan(value interface{}) (err error) {
-	rv := reflect.ValueOf(value)
-	switch rv.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		i.Int64 = rv.Int()
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		i.Int64 = int64(rv.Uint())
-	case reflect.Float32, reflect.Float64:
-		i.Int64 = int64(rv.Float())
+	switch x := value.(type) {
+	case nil:
+		i.Null = true
+	case []byte:
+		i.Int64, err = strconv.ParseInt(string(x), 10, 64)
+	case string:
+		i.Int64, err = strconv.ParseInt(x, 10, 64)
 	default:
-		err = fmt.Errorf("gomu: cannot scan type %T into gomu.Int: %v", value, value)
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i.Int64 = rv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			i.Int64 = int64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			i.Int64 = int64(rv.Float())
+		default:
+			err = fmt.Errorf("gomu: cannot scan type %T into gomu.Int: %v", value, value)
+		}
 	}
 	i.Valid = err == nil
 	return
 }
+
+// Value implements database/sql.Valuer.
+func (i Int) Value() (driver.Value, error) {
+	if !i.Valid || i.Null {
+		return nil, nil
+	}
+	return i.Int64, nil
+}
+
+// Set implements flag.Value. "" and "null" set Int to null.
+func (i *Int) Set(s string) error {
+	return i.updateNullable(func(n *Nullable[int64]) error { return n.Set(s) })
+}
+
+// String implements flag.Value.
+func (i Int) String() string {
+	return i.Nullable().String()
+}
+
+// Get implements flag.Getter. It returns nil if this Int is null or not valid.
+func (i Int) Get() interface{} {
+	return i.Nullable().Get()
+}
+
+// Type returns the name of the value type, as used in the usage message of github.com/spf13/pflag.
+func (i Int) Type() string {
+	return "int"
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+// It will encode null if this Int is null or not valid.
+func (i Int) MarshalYAML() (interface{}, error) {
+	return i.Nullable().MarshalYAML()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also accepts.
+// Note that both packages leave Int unassigned for null instead of calling this method;
+// use github.com/hapoon/gomu/yaml to decode null as Null.
+func (i *Int) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return i.updateNullable(func(n *Nullable[int64]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// IsZero reports whether this Int is not valid, so that the omitempty option of gopkg.in/yaml.v3
+// and the omitzero option of encoding/json omit unassigned values.
+func (i Int) IsZero() bool {
+	return i.Nullable().IsZero()
+}
+
+// MarshalXML implements xml.Marshaler.
+// It will encode nothing if this Int is not valid, and an element with xsi:nil="true" if it is null.
+func (i Int) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return i.Nullable().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+// An element with xsi:nil="true" or without content sets Int to null.
+func (i *Int) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return i.updateNullable(func(n *Nullable[int64]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr.
+// It will encode no attribute if this Int is null or not valid.
+func (i Int) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return i.Nullable().MarshalXMLAttr(name)
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr. An empty attribute sets Int to null.
+func (i *Int) UnmarshalXMLAttr(attr xml.Attr) error {
+	return i.updateNullable(func(n *Nullable[int64]) error { return n.UnmarshalXMLAttr(attr) })
+}
+
+func (i Int) tristate() (null bool, valid bool) {
+	return i.Null, i.Valid
+}
@@ -1,7 +1,9 @@
 package gomu
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -264,3 +266,75 @@ func TestScanInt(t *testing.T) {
 	checkError(err)
 	assert.Equal(t, target, expect, `Scan(12345) fail`)
 }
+
+func TestScanIntNullAndText(t *testing.T) {
+	// nil
+	target := Int{}
+	expect := Int{
+		Int64: 0,
+		Null:  true,
+		Valid: true,
+	}
+	err := target.Scan(nil)
+	checkError(err)
+	assert.Equal(t, target, expect, `Scan(nil) fail`)
+	// []byte
+	target = Int{}
+	expect = Int{
+		Int64: 12345,
+		Null:  false,
+		Valid: true,
+	}
+	err = target.Scan([]byte("12345"))
+	checkError(err)
+	assert.Equal(t, target, expect, `Scan([]byte("12345")) fail`)
+	// string
+	target = Int{}
+	err = target.Scan("12345")
+	checkError(err)
+	assert.Equal(t, target, expect, `Scan("12345") fail`)
+	// not a number
+	target = Int{}
+	err = target.Scan("abc")
+	assert.Error(t, err, `Scan("abc") fail`)
+	assert.False(t, target.Valid, `Scan("abc") fail`)
+}
+
+func TestValueInt(t *testing.T) {
+	// normal 12345
+	i := IntFrom(12345)
+	data, err := i.Value()
+	checkError(err)
+	assert.Equal(t, data, driver.Value(int64(12345)), "Value 12345 fail")
+	// normal null
+	i = IntFromPtr(nil)
+	data, err = i.Value()
+	checkError(err)
+	assert.Nil(t, data, "Value null fail")
+	// normal not assigned
+	i = Int{}
+	data, err = i.Value()
+	checkError(err)
+	assert.Nil(t, data, "Value not assigned fail")
+}
+
+func TestYAMLInt(t *testing.T) {
+	// 1
+	target := Int{}
+	err := target.UnmarshalYAML(func(v interface{}) error {
+		*(v.(**int64)) = &[]int64{1}[0]
+		return nil
+	})
+	checkError(err)
+	assert.Equal(t, IntFrom(1), target, "UnmarshalYAML(1) fail")
+	// error
+	err = target.UnmarshalYAML(func(v interface{}) error { return errors.New("invalid") })
+	assert.Error(t, err, "UnmarshalYAML(error) fail")
+
+	v, err := IntFrom(1).MarshalYAML()
+	checkError(err)
+	assert.Equal(t, int64(1), v, "MarshalYAML() fail")
+	v, err = Int{}.MarshalYAML()
+	checkError(err)
+	assert.Nil(t, v, "MarshalYAML() not valid fail")
+}
@@ -0,0 +1,23 @@
+// Package structtag parses struct tags of the form `name,opt1,opt2`,
+// as shared by gomu and its encoding subpackages.
+package structtag
+
+import "strings"
+
+// Parse splits tag into its name and the comma separated options that follow it.
+func Parse(tag string) (name string, opts string) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+// HasOption reports whether opts, as returned by Parse, contains opt.
+func HasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package structtag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		tag  string
+		name string
+		opts string
+	}{
+		{"", "", ""},
+		{"name", "name", ""},
+		{"name,omitempty", "name", "omitempty"},
+		{",omitempty,flow", "", "omitempty,flow"},
+	}
+	for _, test := range tests {
+		name, opts := Parse(test.tag)
+		assert.Equal(t, test.name, name, "Parse(%q) fail", test.tag)
+		assert.Equal(t, test.opts, opts, "Parse(%q) fail", test.tag)
+	}
+}
+
+func TestHasOption(t *testing.T) {
+	assert.True(t, HasOption("omitempty,flow", "flow"), "HasOption() fail")
+	assert.False(t, HasOption("omitempty,flow", "inline"), "HasOption() fail")
+	assert.False(t, HasOption("", "omitempty"), "HasOption(empty) fail")
+}
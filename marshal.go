@@ -0,0 +1,287 @@
+package gomu
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/hapoon/gomu/internal/structtag"
+)
+
+// Marshal returns the JSON encoding of v like json.Marshal,
+// except that struct fields and map entries holding a gomu value that is not Valid are omitted.
+// Null gomu values are still encoded as null.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshalValue(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encoder writes JSON values to an output stream, omitting unassigned gomu values like Marshal.
+type Encoder struct {
+	w      io.Writer
+	prefix string
+	indent string
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetIndent instructs the encoder to format each subsequent encoded value as if indented by json.Indent.
+func (enc *Encoder) SetIndent(prefix, indent string) {
+	enc.prefix = prefix
+	enc.indent = indent
+}
+
+// Encode writes the JSON encoding of v to the stream, followed by a newline character.
+func (enc *Encoder) Encode(v interface{}) error {
+	b, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	if enc.prefix != "" || enc.indent != "" {
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, b, enc.prefix, enc.indent); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	}
+	_, err = enc.w.Write(append(b, '\n'))
+	return err
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	tristaterType     = reflect.TypeOf((*tristater)(nil)).Elem()
+)
+
+// tristateOf returns the gomu value held by v, which may be a gomu type, a pointer to it or an interface.
+// ok is false for other values and for nil pointers, whose tristate method cannot be called.
+func tristateOf(v reflect.Value) (ts tristater, ok bool) {
+	if v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.CanInterface() || v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, false
+	}
+	ts, ok = v.Interface().(tristater)
+	return
+}
+
+// IsType reports whether t is a gomu type: String, Int, Float, Bool, Time or an instance of Nullable.
+// Pointers to gomu types are not gomu types themselves.
+func IsType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(tristaterType)
+}
+
+// Tristate returns the Null and Valid fields of the gomu value held by v,
+// which may also be a pointer to a gomu value or an interface holding one.
+// ok is false if v holds no gomu value or is a nil pointer.
+func Tristate(v reflect.Value) (null bool, valid bool, ok bool) {
+	ts, ok := tristateOf(v)
+	if !ok {
+		return false, false, false
+	}
+	null, valid = ts.tristate()
+	return null, valid, true
+}
+
+// IsUnassigned reports whether v holds a gomu value that is not Valid.
+// A nil pointer to a gomu type is not unassigned; Marshal encodes it as null like encoding/json does.
+func IsUnassigned(v reflect.Value) bool {
+	_, valid, ok := Tristate(v)
+	return ok && !valid
+}
+
+func marshalValue(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return marshalDefault(buf, v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return marshalValue(buf, v.Elem())
+	case reflect.Struct:
+		return marshalStruct(buf, v)
+	case reflect.Map:
+		return marshalMap(buf, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return marshalDefault(buf, v)
+		}
+		return marshalArray(buf, v)
+	case reflect.Array:
+		return marshalArray(buf, v)
+	default:
+		return marshalDefault(buf, v)
+	}
+}
+
+func marshalDefault(buf *bytes.Buffer, v reflect.Value) error {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+func marshalArray(buf *bytes.Buffer, v reflect.Value) error {
+	buf.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshalValue(buf, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func marshalMap(buf *bytes.Buffer, v reflect.Value) error {
+	if v.IsNil() {
+		buf.WriteString("null")
+		return nil
+	}
+	entries := make(map[string]json.RawMessage, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		if IsUnassigned(iter.Value()) {
+			continue
+		}
+		key, err := mapKeyString(iter.Key())
+		if err != nil {
+			return err
+		}
+		var eb bytes.Buffer
+		if err = marshalValue(&eb, iter.Value()); err != nil {
+			return err
+		}
+		entries[key] = eb.Bytes()
+	}
+	// json.Marshal sorts the keys like it does for any other map.
+	return marshalDefault(buf, reflect.ValueOf(entries))
+}
+
+func mapKeyString(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("gomu: unsupported map key type %s", k.Type())
+}
+
+func marshalStruct(buf *bytes.Buffer, v reflect.Value) error {
+	buf.WriteByte('{')
+	first := true
+	if err := marshalFields(buf, v, &first); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func marshalFields(buf *bytes.Buffer, v reflect.Value, first *bool) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		name, opts := structtag.Parse(sf.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				ft = ft.Elem()
+				fv = fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !ft.Implements(tristaterType) {
+				if err := marshalFields(buf, fv, first); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if IsUnassigned(fv) {
+			continue
+		}
+		if structtag.HasOption(opts, "omitempty") && isEmptyJSONValue(fv) {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+		kb, _ := json.Marshal(name)
+		buf.Write(kb)
+		buf.WriteByte(':')
+		if err := marshalValue(buf, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEmptyJSONValue reports whether v is empty in the sense of the json omitempty option.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
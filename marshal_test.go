@@ -0,0 +1,146 @@
+package gomu
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructMarshalAddress struct {
+	Zip  String `json:"zip"`
+	City String `json:"city,omitempty"`
+}
+
+type testStructMarshalEmbedded struct {
+	Note String `json:"note"`
+}
+
+type testStructMarshal struct {
+	testStructMarshalEmbedded
+	Name    String                    `json:"name"`
+	Age     Int                       `json:"age"`
+	Admin   Bool                      `json:"admin"`
+	Score   Float                     `json:"score"`
+	Kind    Nullable[string]          `json:"kind"`
+	Address testStructMarshalAddress  `json:"address"`
+	Tags    []String                  `json:"tags"`
+	Extra   map[string]Int            `json:"extra,omitempty"`
+	Plain   string                    `json:"plain,omitempty"`
+	Ignored String                    `json:"-"`
+	Ptr     *testStructMarshalAddress `json:"ptr"`
+	private String
+}
+
+func TestMarshal(t *testing.T) {
+	// all assigned
+	ts := testStructMarshal{
+		testStructMarshalEmbedded: testStructMarshalEmbedded{Note: StringFrom("memo")},
+		Name:                      StringFrom("test"),
+		Age:                       IntFrom(20),
+		Admin:                     BoolFrom(false),
+		Score:                     FloatFrom(1.5),
+		Kind:                      NullableFrom("a"),
+		Address:                   testStructMarshalAddress{Zip: StringFrom("100-0001"), City: StringFrom("Tokyo")},
+		Tags:                      []String{StringFrom("x"), StringFromPtr(nil)},
+		Extra:                     map[string]Int{"b": IntFrom(2), "a": IntFrom(1)},
+		Plain:                     "plain",
+		Ignored:                   StringFrom("ignored"),
+	}
+	expect := `{"note":"memo","name":"test","age":20,"admin":false,"score":1.5,"kind":"a","address":{"zip":"100-0001","city":"Tokyo"},"tags":["x",null],"extra":{"a":1,"b":2},"plain":"plain","ptr":null}`
+	target, err := Marshal(ts)
+	checkError(err)
+	assert.Equal(t, expect, string(target), "Marshal(all assigned) fail")
+	// null and not assigned
+	ts = testStructMarshal{
+		Name:    StringFromPtr(nil),
+		Age:     IntFromPtr(nil),
+		Address: testStructMarshalAddress{Zip: StringFromPtr(nil)},
+		Extra:   map[string]Int{"a": IntFromPtr(nil), "b": {}},
+		Ptr:     &testStructMarshalAddress{City: StringFrom("Osaka")},
+	}
+	expect = `{"name":null,"age":null,"address":{"zip":null},"tags":null,"extra":{"a":null},"ptr":{"city":"Osaka"}}`
+	target, err = Marshal(ts)
+	checkError(err)
+	assert.Equal(t, expect, string(target), "Marshal(null and not assigned) fail")
+	// round trip keeps absent keys absent
+	var decoded testStructMarshalAddress
+	err = json.Unmarshal([]byte(`{"city":null}`), &decoded)
+	checkError(err)
+	target, err = Marshal(decoded)
+	checkError(err)
+	assert.Equal(t, `{"city":null}`, string(target), "Marshal(round trip) fail")
+	// pointers to gomu types
+	unassigned, assigned := String{}, StringFrom("v")
+	target, err = Marshal(struct {
+		S *String `json:"s"`
+		U *String `json:"u"`
+		V *String `json:"v"`
+		N Int     `json:"n"`
+		M map[string]interface{}
+	}{U: &unassigned, V: &assigned, M: map[string]interface{}{"a": (*String)(nil)}})
+	checkError(err)
+	assert.Equal(t, `{"s":null,"v":"v","M":{"a":null}}`, string(target), "Marshal(nil *String) fail")
+	// an option containing omitempty is not omitempty
+	target, err = Marshal(struct {
+		Z int `json:"z,notomitempty"`
+	}{})
+	checkError(err)
+	assert.Equal(t, `{"z":0}`, string(target), "Marshal(notomitempty) fail")
+	// non struct values
+	target, err = Marshal(String{})
+	checkError(err)
+	assert.Equal(t, "null", string(target), "Marshal(String{}) fail")
+	target, err = Marshal(nil)
+	checkError(err)
+	assert.Equal(t, "null", string(target), "Marshal(nil) fail")
+	// unsupported value
+	_, err = Marshal(map[string]interface{}{"ch": make(chan int)})
+	assert.Error(t, err, "Marshal(chan) fail")
+}
+
+func TestTristate(t *testing.T) {
+	assert.True(t, IsType(reflect.TypeOf(String{})), "IsType(String) fail")
+	assert.True(t, IsType(reflect.TypeOf(Nullable[int]{})), "IsType(Nullable[int]) fail")
+	assert.False(t, IsType(reflect.TypeOf(&String{})), "IsType(*String) fail")
+	assert.False(t, IsType(reflect.TypeOf(testStructMarshalAddress{})), "IsType(struct) fail")
+	null, assigned := StringFromPtr(nil), IntFrom(1)
+	var tests = []struct {
+		v          interface{}
+		null       bool
+		valid      bool
+		ok         bool
+		unassigned bool
+	}{
+		{String{}, false, false, true, true},
+		{null, true, true, true, false},
+		{&assigned, false, true, true, false},
+		{(*Int)(nil), false, false, false, false},
+		{"string", false, false, false, false},
+	}
+	for _, test := range tests {
+		v := reflect.ValueOf(test.v)
+		n, valid, ok := Tristate(v)
+		assert.Equal(t, []bool{test.null, test.valid, test.ok}, []bool{n, valid, ok}, "Tristate(%#v) fail", test.v)
+		assert.Equal(t, test.unassigned, IsUnassigned(v), "IsUnassigned(%#v) fail", test.v)
+	}
+	// interfaces holding gomu values
+	m := map[string]interface{}{"a": Bool{}}
+	assert.True(t, IsUnassigned(reflect.ValueOf(m).MapIndex(reflect.ValueOf("a"))), "IsUnassigned(interface) fail")
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	err := enc.Encode(testStructMarshalAddress{Zip: StringFrom("100-0001")})
+	checkError(err)
+	assert.Equal(t, "{\"zip\":\"100-0001\"}\n", buf.String(), "Encode() fail")
+	// indent
+	buf.Reset()
+	enc.SetIndent("", "  ")
+	err = enc.Encode(testStructMarshalAddress{Zip: StringFrom("100-0001")})
+	checkError(err)
+	assert.Equal(t, "{\n  \"zip\": \"100-0001\"\n}\n", buf.String(), "Encode(indent) fail")
+}
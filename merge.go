@@ -0,0 +1,50 @@
+package gomu
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merge overlays layers onto dst in order, so that later layers win, with the default Merger.
+func Merge(dst interface{}, layers ...interface{}) error {
+	return (&Merger{}).Merge(dst, layers...)
+}
+
+// Merger overlays the assigned fields of layers, such as defaults, a config file, environment variables and flags.
+//
+// Each layer is merged onto dst like ApplyPatch: gomu fields that are not Valid and nil pointers are skipped,
+// nested structs are merged recursively, slices are replaced and maps are merged by key.
+type Merger struct {
+	// IgnoreNull keeps the value of an earlier layer when a later layer is Null.
+	// By default a Null value clears it, so that dst becomes Null.
+	IgnoreNull bool
+}
+
+// Merge overlays layers onto dst in order. dst must be a non-nil pointer to a struct,
+// and each layer a struct or a pointer to one. Nil layers are skipped.
+func (m *Merger) Merge(dst interface{}, layers ...interface{}) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gomu: Merge requires a non-nil pointer to a struct; got %T", dst)
+	}
+	p := patcher{ignoreNull: m.IgnoreNull}
+	for i, layer := range layers {
+		lv := reflect.ValueOf(layer)
+		for lv.Kind() == reflect.Ptr || lv.Kind() == reflect.Interface {
+			if lv.IsNil() {
+				break
+			}
+			lv = lv.Elem()
+		}
+		if !lv.IsValid() || lv.Kind() == reflect.Ptr || lv.Kind() == reflect.Interface {
+			continue
+		}
+		if lv.Kind() != reflect.Struct {
+			return fmt.Errorf("gomu: Merge only accepts struct layers; got %s at layer %d", lv.Kind(), i)
+		}
+		if err := p.patchStruct(dv.Elem(), lv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package gomu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructMergeDB struct {
+	Host String
+	Port Int
+}
+
+type testStructMerge struct {
+	Addr    String
+	Debug   Bool
+	Timeout Int
+	DB      testStructMergeDB
+	Hosts   []string
+	Labels  map[string]String
+}
+
+func TestMerge(t *testing.T) {
+	defaults := testStructMerge{
+		Addr:    StringFrom(":8080"),
+		Debug:   BoolFrom(false),
+		Timeout: IntFrom(30),
+		DB:      testStructMergeDB{Host: StringFrom("localhost"), Port: IntFrom(5432)},
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]String{"env": StringFrom("dev"), "team": StringFrom("core")},
+	}
+	file := testStructMerge{
+		Timeout: IntFrom(10),
+		DB:      testStructMergeDB{Host: StringFrom("db.local")},
+		Hosts:   []string{"c"},
+		Labels:  map[string]String{"env": StringFrom("prd"), "team": StringFromPtr(nil)},
+	}
+	env := &testStructMerge{
+		Debug:   BoolFrom(true),
+		Timeout: IntFromPtr(nil),
+	}
+	var flags *testStructMerge
+
+	var target testStructMerge
+	err := Merge(&target, defaults, file, env, flags)
+	checkError(err)
+	expect := testStructMerge{
+		Addr:    StringFrom(":8080"),
+		Debug:   BoolFrom(true),
+		Timeout: IntFromPtr(nil),
+		DB:      testStructMergeDB{Host: StringFrom("db.local"), Port: IntFrom(5432)},
+		Hosts:   []string{"c"},
+		Labels:  map[string]String{"env": StringFrom("prd")},
+	}
+	assert.Equal(t, expect, target, "Merge() fail")
+
+	// Null does not clear earlier values
+	target = testStructMerge{}
+	m := &Merger{IgnoreNull: true}
+	err = m.Merge(&target, defaults, file, env)
+	checkError(err)
+	expect.Timeout = IntFrom(10)
+	expect.Labels = map[string]String{"env": StringFrom("prd"), "team": StringFrom("core")}
+	assert.Equal(t, expect, target, "Merge(IgnoreNull) fail")
+}
+
+func TestMergePointer(t *testing.T) {
+	type testStructMergePointer struct {
+		Addr  *String
+		Token *String
+		Port  Int
+	}
+	addr, null := StringFrom(":8080"), StringFromPtr(nil)
+	defaults := testStructMergePointer{Addr: &addr, Port: IntFrom(80)}
+	file := testStructMergePointer{Addr: nil, Token: nil, Port: IntFrom(8080)}
+	env := &testStructMergePointer{Token: &null}
+
+	var target testStructMergePointer
+	err := Merge(&target, defaults, file, env)
+	checkError(err)
+	assert.Equal(t, testStructMergePointer{Addr: &addr, Token: &null, Port: IntFrom(8080)}, target, "Merge(nil *String) fail")
+
+	// Null does not clear earlier values
+	target = testStructMergePointer{}
+	m := &Merger{IgnoreNull: true}
+	err = m.Merge(&target, defaults, file, env)
+	checkError(err)
+	assert.Equal(t, testStructMergePointer{Addr: &addr, Port: IntFrom(8080)}, target, "Merge(IgnoreNull, nil *String) fail")
+}
+
+func TestMergeInvalidArgs(t *testing.T) {
+	var target testStructMerge
+	err := Merge(target, testStructMerge{})
+	assert.Error(t, err, "Merge(not pointer) fail")
+	err = Merge(&target, "layer")
+	assert.Error(t, err, "Merge(string layer) fail")
+}
@@ -0,0 +1,262 @@
+// Package msgpack encodes and decodes gomu values with github.com/vmihailenco/msgpack/v5.
+//
+// Importing the package registers encoders and decoders for String, Int, Float, Bool and Time,
+// so that msgpack.Marshal encodes Null and unassigned values as nil, and msgpack.Unmarshal
+// decodes nil as Null like UnmarshalJSON does. Nullable types are registered with RegisterNullable.
+//
+// msgpack.Marshal omits unassigned values only for fields with the omitempty option,
+// while Marshal omits every struct field and map entry holding an unassigned gomu value.
+package msgpack
+
+import (
+	"bytes"
+	"reflect"
+	"time"
+
+	"github.com/hapoon/gomu"
+	"github.com/hapoon/gomu/internal/structtag"
+	msgpackv5 "github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+func init() {
+	register(gomu.String.Nullable, gomu.StringFromNullable)
+	register(gomu.Int.Nullable, gomu.IntFromNullable)
+	register(gomu.Float.Nullable, gomu.FloatFromNullable)
+	register(gomu.Bool.Nullable, gomu.BoolFromNullable)
+	register(gomu.Time.Nullable, gomu.TimeFromNullable)
+}
+
+// RegisterNullable registers the encoder and decoder of gomu.Nullable[T].
+func RegisterNullable[T any]() {
+	identity := func(n gomu.Nullable[T]) gomu.Nullable[T] { return n }
+	register(identity, identity)
+}
+
+// register registers the encoder and decoder of the gomu type G, which converts to and from gomu.Nullable[T].
+func register[G, T any](toNullable func(G) gomu.Nullable[T], fromNullable func(gomu.Nullable[T]) G) {
+	var zero G
+	msgpackv5.Register(zero,
+		func(e *msgpackv5.Encoder, v reflect.Value) error {
+			n := toNullable(v.Interface().(G))
+			if !n.Valid || n.Null {
+				return e.EncodeNil()
+			}
+			return e.Encode(n.Val)
+		},
+		func(d *msgpackv5.Decoder, v reflect.Value) error {
+			code, err := d.PeekCode()
+			if err != nil {
+				return err
+			}
+			var n gomu.Nullable[T]
+			if code == msgpcode.Nil {
+				if err = d.DecodeNil(); err != nil {
+					return err
+				}
+				n.Null = true
+			} else if err = d.Decode(&n.Val); err != nil {
+				return err
+			}
+			n.Valid = true
+			v.Set(reflect.ValueOf(fromNullable(n)))
+			return nil
+		})
+}
+
+// Marshal returns the MessagePack encoding of v like msgpack.Marshal,
+// except that struct fields and map entries holding a gomu value that is not Valid are omitted.
+// Null gomu values are still encoded as nil.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(msgpackv5.NewEncoder(&buf), reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the MessagePack-encoded data into v like msgpack.Unmarshal.
+// gomu values whose key is missing are left unassigned (Valid is false), and nil sets them to Null.
+func Unmarshal(data []byte, v interface{}) error {
+	return msgpackv5.Unmarshal(data, v)
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	customEncoderType = reflect.TypeOf((*msgpackv5.CustomEncoder)(nil)).Elem()
+	marshalerType     = reflect.TypeOf((*msgpackv5.Marshaler)(nil)).Elem()
+)
+
+// isLeaf reports whether values of type t are encoded by msgpack as a whole.
+func isLeaf(t reflect.Type) bool {
+	return gomu.IsType(t) || t == timeType ||
+		t.Implements(customEncoderType) || reflect.PtrTo(t).Implements(customEncoderType) ||
+		t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)
+}
+
+func encodeValue(e *msgpackv5.Encoder, v reflect.Value) error {
+	if !v.IsValid() {
+		return e.EncodeNil()
+	}
+	if isLeaf(v.Type()) {
+		return e.EncodeValue(v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return e.EncodeNil()
+		}
+		return encodeValue(e, v.Elem())
+	case reflect.Struct:
+		return encodeStruct(e, v)
+	case reflect.Map:
+		if v.IsNil() {
+			return e.EncodeNil()
+		}
+		n := 0
+		iter := v.MapRange()
+		for iter.Next() {
+			if !gomu.IsUnassigned(iter.Value()) {
+				n++
+			}
+		}
+		if err := e.EncodeMapLen(n); err != nil {
+			return err
+		}
+		iter = v.MapRange()
+		for iter.Next() {
+			if gomu.IsUnassigned(iter.Value()) {
+				continue
+			}
+			if err := e.EncodeValue(iter.Key()); err != nil {
+				return err
+			}
+			if err := encodeValue(e, iter.Value()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return e.EncodeValue(v)
+		}
+		if err := e.EncodeArrayLen(v.Len()); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(e, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return e.EncodeValue(v)
+	}
+}
+
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+func encodeStruct(e *msgpackv5.Encoder, v reflect.Value) error {
+	fields, ok := structFields(v.Type(), nil)
+	if !ok {
+		return e.EncodeValue(v)
+	}
+	encoded := make([]field, 0, len(fields))
+	values := make([]reflect.Value, 0, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || gomu.IsUnassigned(fv) || f.omitEmpty && isEmpty(fv) {
+			continue
+		}
+		encoded = append(encoded, f)
+		values = append(values, fv)
+	}
+	if err := e.EncodeMapLen(len(encoded)); err != nil {
+		return err
+	}
+	for i, f := range encoded {
+		if err := e.EncodeString(f.name); err != nil {
+			return err
+		}
+		if err := encodeValue(e, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structFields returns the fields of struct type t following the rules of msgpack:
+// the key is the name in the `msgpack` tag or the field name, and embedded structs without a name are inlined.
+// It returns false if t is encoded as an array by the as_array option.
+func structFields(t reflect.Type, index []int) ([]field, bool) {
+	var fields []field
+	omitEmpty := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := structtag.Parse(sf.Tag.Get("msgpack"))
+		if sf.Name == "_msgpack" {
+			if structtag.HasOption(opts, "as_array") || structtag.HasOption(opts, "asArray") {
+				return nil, false
+			}
+			omitEmpty = structtag.HasOption(opts, "omitempty")
+		}
+		if name == "-" || sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && !structtag.HasOption(opts, "noinline") && ft.Kind() == reflect.Struct && !isLeaf(ft) {
+			inlined, ok := structFields(ft, idx)
+			if !ok {
+				return nil, false
+			}
+			fields = append(fields, inlined...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{name: name, index: idx, omitEmpty: omitEmpty || structtag.HasOption(opts, "omitempty")})
+	}
+	return fields, true
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but returns false for fields of nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmpty reports whether v is empty in the sense of the msgpack omitempty option.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
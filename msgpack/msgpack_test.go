@@ -0,0 +1,90 @@
+package msgpack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+	msgpackv5 "github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	RegisterNullable[int]()
+}
+
+type testStructMsgpackBase struct {
+	ID gomu.Int `msgpack:"id"`
+}
+
+type testStructMsgpack struct {
+	testStructMsgpackBase
+	Name   gomu.String         `msgpack:"name"`
+	Age    gomu.Int            `msgpack:"age"`
+	Ratio  gomu.Float          `msgpack:"ratio"`
+	Active gomu.Bool           `msgpack:"active"`
+	Born   gomu.Time           `msgpack:"born"`
+	Score  gomu.Nullable[int]  `msgpack:"score"`
+	Labels map[string]gomu.Int `msgpack:"labels"`
+	Tags   []gomu.String       `msgpack:"tags"`
+}
+
+func TestMarshal(t *testing.T) {
+	born := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	src := testStructMsgpack{
+		testStructMsgpackBase: testStructMsgpackBase{ID: gomu.IntFrom(1)},
+		Name:                  gomu.StringFrom("foo"),
+		Age:                   gomu.NewInt(0, true, true),
+		Born:                  gomu.TimeFrom(born),
+		Score:                 gomu.NullableFrom(3),
+		Labels:                map[string]gomu.Int{"x": gomu.IntFrom(1), "y": {}},
+		Tags:                  []gomu.String{gomu.StringFrom("a"), gomu.NewString("", true, true)},
+	}
+	data, err := Marshal(src)
+	assert.NoError(t, err, "Marshal fail")
+
+	var keys map[string]interface{}
+	err = msgpackv5.Unmarshal(data, &keys)
+	assert.NoError(t, err, "Unmarshal keys fail")
+	assert.Contains(t, keys, "age", "Null age fail")
+	assert.Nil(t, keys["age"], "Null age value fail")
+	assert.NotContains(t, keys, "ratio", "unassigned ratio fail")
+	assert.NotContains(t, keys, "active", "unassigned active fail")
+	assert.Equal(t, map[string]interface{}{"x": int64(1)}, keys["labels"], "labels fail")
+
+	var target testStructMsgpack
+	err = Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, src.ID, target.ID, "ID fail")
+	assert.Equal(t, src.Name, target.Name, "Name fail")
+	assert.Equal(t, src.Age, target.Age, "Age fail")
+	assert.False(t, target.Ratio.Valid, "Ratio fail")
+	assert.False(t, target.Active.Valid, "Active fail")
+	assert.True(t, born.Equal(target.Born.Time), "Born fail")
+	assert.Equal(t, src.Score, target.Score, "Score fail")
+	assert.Equal(t, map[string]gomu.Int{"x": gomu.IntFrom(1)}, target.Labels, "Labels fail")
+	assert.Equal(t, src.Tags, target.Tags, "Tags fail")
+}
+
+func TestRegister(t *testing.T) {
+	data, err := msgpackv5.Marshal(testStructMsgpack{Name: gomu.StringFrom("foo")})
+	assert.NoError(t, err, "msgpack.Marshal fail")
+
+	var keys map[string]interface{}
+	err = msgpackv5.Unmarshal(data, &keys)
+	assert.NoError(t, err, "Unmarshal keys fail")
+	assert.Equal(t, "foo", keys["name"], "name fail")
+	assert.Contains(t, keys, "age", "unassigned age fail")
+	assert.Nil(t, keys["age"], "unassigned age value fail")
+
+	var target testStructMsgpack
+	err = msgpackv5.Unmarshal(data, &target)
+	assert.NoError(t, err, "msgpack.Unmarshal fail")
+	assert.Equal(t, gomu.StringFrom("foo"), target.Name, "Name fail")
+	assert.Equal(t, gomu.NewInt(0, true, true), target.Age, "Age fail")
+
+	err = msgpackv5.Unmarshal(data, &struct {
+		Name gomu.Int `msgpack:"name"`
+	}{})
+	assert.Error(t, err, "type mismatch fail")
+}
@@ -0,0 +1,420 @@
+package gomu
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nullable is a nullable value of any type.
+// If key is assigned and value is not null, Val is specified value, Null is false, Valid is true.
+// If value is null, Null is true.
+// If key is not assigned, Valid is false.
+//
+// String, Int, Float, Bool and Time keep their own field names for compatibility,
+// and can be converted to and from their Nullable counterpart, to which they delegate
+// their flag, YAML, XML and BSON methods.
+type Nullable[T any] struct {
+	Val   T
+	Null  bool
+	Valid bool
+}
+
+// NewNullable creates a new Nullable.
+func NewNullable[T any](v T, n bool, valid bool) Nullable[T] {
+	return Nullable[T]{
+		Val:   v,
+		Null:  n,
+		Valid: valid,
+	}
+}
+
+// NullableFrom creates a new Nullable that will always be valid.
+func NullableFrom[T any](v T) Nullable[T] {
+	return NewNullable(v, false, true)
+}
+
+// NullableFromPtr creates a new Nullable that will be null if v is nil.
+func NullableFromPtr[T any](v *T) Nullable[T] {
+	if v == nil {
+		var zero T
+		return NewNullable(zero, true, true)
+	}
+	return NewNullable(*v, false, true)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Null = true
+		n.Valid = true
+		return
+	}
+	err = json.Unmarshal(data, &n.Val)
+	n.Valid = err == nil
+	return
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// T is decoded with its own encoding.TextUnmarshaler if it has one, taken verbatim if it is a string kind,
+// parsed with strconv if it is a bool or number kind, and parsed as a JSON scalar otherwise.
+func (n *Nullable[T]) UnmarshalText(text []byte) (err error) {
+	if text == nil {
+		return
+	}
+	str := string(text)
+	if str == "" || str == "null" {
+		n.Null = true
+		n.Valid = true
+		return
+	}
+	err = n.unmarshalVal(text)
+	n.Valid = err == nil
+	return
+}
+
+func (n *Nullable[T]) unmarshalVal(text []byte) (err error) {
+	if u, ok := interface{}(&n.Val).(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText(text)
+	}
+	rv := reflect.ValueOf(&n.Val).Elem()
+	str := string(text)
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(str)
+		return nil
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(str); err == nil {
+			rv.SetBool(b)
+		}
+		return
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(str, 10, rv.Type().Bits()); err == nil {
+			rv.SetInt(i)
+		}
+		return
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(str, 10, rv.Type().Bits()); err == nil {
+			rv.SetUint(u)
+		}
+		return
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(str, rv.Type().Bits()); err == nil {
+			rv.SetFloat(f)
+		}
+		return
+	}
+	if err := json.Unmarshal(text, &n.Val); err != nil {
+		return fmt.Errorf("gomu: cannot unmarshal %q into Go value of type %T", text, n.Val)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid || n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Val)
+}
+
+// MarshalText implements encoding.TextMarshaler. Floats are written without an exponent, like Float.
+func (n Nullable[T]) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if n.Null {
+		return []byte("null"), nil
+	}
+	if m, ok := interface{}(n.Val).(encoding.TextMarshaler); ok {
+		return m.MarshalText()
+	}
+	if rv := reflect.ValueOf(n.Val); rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64 {
+		return []byte(strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())), nil
+	}
+	return []byte(fmt.Sprint(n.Val)), nil
+}
+
+// SetValid changes this Nullable's value and also sets Valid to be true.
+func (n *Nullable[T]) SetValid(v T) {
+	n.Val = v
+	n.Null = false
+	n.Valid = true
+}
+
+// Ptr returns a pointer to this Nullable's value, or a nil pointer if this Nullable is null or not valid.
+func (n Nullable[T]) Ptr() *T {
+	if !n.Valid || n.Null {
+		return nil
+	}
+	return &n.Val
+}
+
+// Scan implements database/sql.Scanner.
+func (n *Nullable[T]) Scan(value interface{}) (err error) {
+	if value == nil {
+		n.Null = true
+		n.Valid = true
+		return
+	}
+	if s, ok := interface{}(&n.Val).(sql.Scanner); ok {
+		err = s.Scan(value)
+		n.Valid = err == nil
+		return
+	}
+	switch x := value.(type) {
+	case T:
+		n.Val = x
+	case []byte:
+		err = n.unmarshalVal(x)
+	case string:
+		err = n.unmarshalVal([]byte(x))
+	default:
+		rv := reflect.ValueOf(value)
+		dv := reflect.ValueOf(&n.Val).Elem()
+		if isNumberKind(rv.Kind()) && isNumberKind(dv.Kind()) {
+			dv.Set(rv.Convert(dv.Type()))
+		} else {
+			err = fmt.Errorf("gomu: cannot scan type %T into gomu.Nullable[%T]: %v", value, n.Val, value)
+		}
+	}
+	n.Valid = err == nil
+	return
+}
+
+// Value implements database/sql.Valuer.
+func (n Nullable[T]) Value() (driver.Value, error) {
+	if !n.Valid || n.Null {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.Val)
+}
+
+// Set implements flag.Value. "" and "null" set Nullable to null.
+func (n *Nullable[T]) Set(s string) error {
+	*n = Nullable[T]{}
+	return n.UnmarshalText([]byte(s))
+}
+
+// String implements flag.Value.
+func (n Nullable[T]) String() string {
+	text, _ := n.MarshalText()
+	return string(text)
+}
+
+// Get implements flag.Getter. It returns nil if this Nullable is null or not valid.
+func (n Nullable[T]) Get() interface{} {
+	if !n.Valid || n.Null {
+		return nil
+	}
+	return n.Val
+}
+
+// Type returns the name of the value type, as used in the usage message of github.com/spf13/pflag.
+func (n Nullable[T]) Type() string {
+	return fmt.Sprintf("%T", n.Val)
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+// It will encode null if this Nullable is null or not valid.
+func (n Nullable[T]) MarshalYAML() (interface{}, error) {
+	if !n.Valid || n.Null {
+		return nil, nil
+	}
+	return n.Val, nil
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also accepts.
+// Note that both packages leave Nullable unassigned for null instead of calling this method;
+// use github.com/hapoon/gomu/yaml to decode null as Null.
+func (n *Nullable[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v *T
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	*n = Nullable[T]{}
+	if v == nil {
+		n.Null = true
+	} else {
+		n.Val = *v
+	}
+	n.Valid = true
+	return nil
+}
+
+// IsZero reports whether this Nullable is not valid, so that the omitempty option of gopkg.in/yaml.v3
+// and the omitzero option of encoding/json omit unassigned values.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Valid
+}
+
+// MarshalXML implements xml.Marshaler.
+// It will encode nothing if this Nullable is not valid, and an element with xsi:nil="true" if it is null.
+func (n Nullable[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !n.Valid {
+		return nil
+	}
+	if n.Null {
+		return encodeXSINil(e, start)
+	}
+	return e.EncodeElement(n.Val, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler. An element with xsi:nil="true" sets Nullable to null,
+// and so does one without content unless T is a string kind.
+func (n *Nullable[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	text, null, err := decodeXMLText(d, start)
+	if err != nil {
+		return err
+	}
+	if null {
+		var zero T
+		*n = NewNullable(zero, true, true)
+		return nil
+	}
+	return n.unmarshalXMLText(text)
+}
+
+// unmarshalXMLText takes text verbatim if T is a string kind, and trims it with Set otherwise.
+func (n *Nullable[T]) unmarshalXMLText(text string) (err error) {
+	*n = Nullable[T]{}
+	if reflect.ValueOf(&n.Val).Elem().Kind() != reflect.String {
+		return n.Set(strings.TrimSpace(text))
+	}
+	err = n.unmarshalVal([]byte(text))
+	n.Valid = err == nil
+	return
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr.
+// It will encode no attribute if this Nullable is null or not valid.
+func (n Nullable[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !n.Valid || n.Null {
+		return xml.Attr{}, nil
+	}
+	text, err := n.MarshalText()
+	return xml.Attr{Name: name, Value: string(text)}, err
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr.
+// An empty attribute sets Nullable to null unless T is a string kind.
+func (n *Nullable[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return n.unmarshalXMLText(attr.Value)
+}
+
+func (n Nullable[T]) tristate() (null bool, valid bool) {
+	return n.Null, n.Valid
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Nullable converts this String to a Nullable[string].
+func (s String) Nullable() Nullable[string] {
+	return NewNullable(s.String, s.Null, s.Valid)
+}
+
+// StringFromNullable creates a new String from a Nullable[string].
+func StringFromNullable(n Nullable[string]) String {
+	return NewString(n.Val, n.Null, n.Valid)
+}
+
+// updateNullable calls update with the Nullable counterpart of this String and stores the result back.
+func (s *String) updateNullable(update func(n *Nullable[string]) error) error {
+	n := s.Nullable()
+	err := update(&n)
+	*s = StringFromNullable(n)
+	return err
+}
+
+// Nullable converts this Int to a Nullable[int64].
+func (i Int) Nullable() Nullable[int64] {
+	return NewNullable(i.Int64, i.Null, i.Valid)
+}
+
+// IntFromNullable creates a new Int from a Nullable[int64].
+func IntFromNullable(n Nullable[int64]) Int {
+	return NewInt(n.Val, n.Null, n.Valid)
+}
+
+// updateNullable calls update with the Nullable counterpart of this Int and stores the result back.
+func (i *Int) updateNullable(update func(n *Nullable[int64]) error) error {
+	n := i.Nullable()
+	err := update(&n)
+	*i = IntFromNullable(n)
+	return err
+}
+
+// Nullable converts this Float to a Nullable[float64].
+func (f Float) Nullable() Nullable[float64] {
+	return NewNullable(f.Float64, f.Null, f.Valid)
+}
+
+// FloatFromNullable creates a new Float from a Nullable[float64].
+func FloatFromNullable(n Nullable[float64]) Float {
+	return NewFloat(n.Val, n.Null, n.Valid)
+}
+
+// updateNullable calls update with the Nullable counterpart of this Float and stores the result back.
+func (f *Float) updateNullable(update func(n *Nullable[float64]) error) error {
+	n := f.Nullable()
+	err := update(&n)
+	*f = FloatFromNullable(n)
+	return err
+}
+
+// Nullable converts this Bool to a Nullable[bool].
+func (b Bool) Nullable() Nullable[bool] {
+	return NewNullable(b.Bool, b.Null, b.Valid)
+}
+
+// BoolFromNullable creates a new Bool from a Nullable[bool].
+func BoolFromNullable(n Nullable[bool]) Bool {
+	return NewBool(n.Val, n.Null, n.Valid)
+}
+
+// updateNullable calls update with the Nullable counterpart of this Bool and stores the result back.
+func (b *Bool) updateNullable(update func(n *Nullable[bool]) error) error {
+	n := b.Nullable()
+	err := update(&n)
+	*b = BoolFromNullable(n)
+	return err
+}
+
+// Nullable converts this Time to a Nullable[time.Time].
+func (t Time) Nullable() Nullable[time.Time] {
+	return NewNullable(t.Time, t.Null, t.Valid)
+}
+
+// TimeFromNullable creates a new Time from a Nullable[time.Time].
+func TimeFromNullable(n Nullable[time.Time]) Time {
+	return NewTime(n.Val, n.Null, n.Valid)
+}
+
+// updateNullable calls update with the Nullable counterpart of this Time and stores the result back.
+func (t *Time) updateNullable(update func(n *Nullable[time.Time]) error) error {
+	n := t.Nullable()
+	err := update(&n)
+	*t = TimeFromNullable(n)
+	return err
+}
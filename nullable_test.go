@@ -0,0 +1,297 @@
+package gomu
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEnum string
+
+type testUpperText string
+
+func (u *testUpperText) UnmarshalText(text []byte) error {
+	*u = testUpperText(strings.ToUpper(string(text)))
+	return nil
+}
+
+func (u testUpperText) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(string(u))), nil
+}
+
+type testStructNullable struct {
+	Name  Nullable[string]    `json:"name"`
+	Count Nullable[int]       `json:"count"`
+	Kind  Nullable[testEnum]  `json:"kind"`
+	At    Nullable[time.Time] `json:"at"`
+}
+
+func TestNullableFrom(t *testing.T) {
+	target := NullableFrom("test")
+	expect := Nullable[string]{
+		Val:   "test",
+		Null:  false,
+		Valid: true,
+	}
+	assert.Equal(t, target, expect, `NullableFrom("test") fail`)
+}
+
+func TestNullableFromPtr(t *testing.T) {
+	// pointer
+	n := 12345
+	target := NullableFromPtr(&n)
+	expect := Nullable[int]{
+		Val:   12345,
+		Null:  false,
+		Valid: true,
+	}
+	assert.Equal(t, target, expect, "NullableFromPtr() fail")
+	// nil
+	target = NullableFromPtr[int](nil)
+	expect = Nullable[int]{
+		Val:   0,
+		Null:  true,
+		Valid: true,
+	}
+	assert.Equal(t, target, expect, "NullableFromPtr(nil) fail")
+}
+
+func TestUnmarshalJSONNullable(t *testing.T) {
+	// key and value assigned
+	j := []byte(`{"name":"test","count":3,"kind":"a","at":"` + timeString + `"}`)
+	target := testStructNullable{}
+	err := json.Unmarshal(j, &target)
+	checkError(err)
+	expect := testStructNullable{
+		Name:  NullableFrom("test"),
+		Count: NullableFrom(3),
+		Kind:  NullableFrom(testEnum("a")),
+		At:    NullableFrom(timeObj),
+	}
+	assert.Equal(t, target, expect, "UnmarshalJSON fail")
+	// value is null
+	j = []byte(`{"name":null,"count":null,"kind":null,"at":null}`)
+	target = testStructNullable{}
+	err = json.Unmarshal(j, &target)
+	checkError(err)
+	expect = testStructNullable{
+		Name:  NullableFromPtr[string](nil),
+		Count: NullableFromPtr[int](nil),
+		Kind:  NullableFromPtr[testEnum](nil),
+		At:    NullableFromPtr[time.Time](nil),
+	}
+	assert.Equal(t, target, expect, "UnmarshalJSON(null) fail")
+	// key is not assigned
+	j = []byte(`{}`)
+	target = testStructNullable{}
+	err = json.Unmarshal(j, &target)
+	checkError(err)
+	assert.Equal(t, target, testStructNullable{}, "UnmarshalJSON(key is not assigned) fail")
+	// wrong type
+	j = []byte(`{"count":"abc"}`)
+	target = testStructNullable{}
+	err = json.Unmarshal(j, &target)
+	assert.Error(t, err, "UnmarshalJSON(wrong type) fail")
+	assert.False(t, target.Count.Valid, "UnmarshalJSON(wrong type) fail")
+}
+
+func TestUnmarshalTextNullable(t *testing.T) {
+	// string
+	s := Nullable[string]{}
+	err := s.UnmarshalText([]byte("test"))
+	checkError(err)
+	assert.Equal(t, s, NullableFrom("test"), "UnmarshalText(string) fail")
+	// int
+	i := Nullable[int]{}
+	err = i.UnmarshalText([]byte("12345"))
+	checkError(err)
+	assert.Equal(t, i, NullableFrom(12345), "UnmarshalText(int) fail")
+	// encoding.TextUnmarshaler
+	u := Nullable[testUpperText]{}
+	err = u.UnmarshalText([]byte("abc"))
+	checkError(err)
+	assert.Equal(t, u, NullableFrom(testUpperText("ABC")), "UnmarshalText(TextUnmarshaler) fail")
+	// bool and number kinds are parsed with strconv
+	b := Nullable[bool]{}
+	err = b.UnmarshalText([]byte("1"))
+	checkError(err)
+	assert.Equal(t, b, NullableFrom(true), "UnmarshalText(bool) fail")
+	i = Nullable[int]{}
+	err = i.UnmarshalText([]byte("+007"))
+	checkError(err)
+	assert.Equal(t, i, NullableFrom(7), "UnmarshalText(+007) fail")
+	// "null"
+	i = Nullable[int]{}
+	err = i.UnmarshalText([]byte("null"))
+	checkError(err)
+	assert.Equal(t, i, NullableFromPtr[int](nil), `UnmarshalText("null") fail`)
+	// nil
+	i = Nullable[int]{}
+	err = i.UnmarshalText(nil)
+	checkError(err)
+	assert.Equal(t, i, Nullable[int]{}, "UnmarshalText(nil) fail")
+	// not a number
+	i = Nullable[int]{}
+	err = i.UnmarshalText([]byte("abc"))
+	assert.Error(t, err, "UnmarshalText(abc) fail")
+	assert.False(t, i.Valid, "UnmarshalText(abc) fail")
+}
+
+func TestMarshalJSONNullable(t *testing.T) {
+	// value assigned
+	ts := testStructNullable{
+		Name:  NullableFrom("test"),
+		Count: NullableFrom(3),
+		Kind:  NullableFromPtr[testEnum](nil),
+	}
+	expect := []byte(`{"name":"test","count":3,"kind":null,"at":null}`)
+	target, err := json.Marshal(ts)
+	checkError(err)
+	assert.Equal(t, string(target), string(expect), "MarshalJSON fail")
+}
+
+func TestMarshalTextNullable(t *testing.T) {
+	// value assigned
+	target, err := NullableFrom(12345).MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte("12345"), "MarshalText(12345) fail")
+	// encoding.TextMarshaler
+	target, err = NullableFrom(testUpperText("ABC")).MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte("abc"), "MarshalText(TextMarshaler) fail")
+	// float without an exponent like Float
+	target, err = NullableFrom(1e21).MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte("1000000000000000000000"), "MarshalText(1e21) fail")
+	// value is null
+	target, err = NullableFromPtr[int](nil).MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte("null"), "MarshalText(null) fail")
+	// key is not assigned
+	target, err = Nullable[int]{}.MarshalText()
+	checkError(err)
+	assert.Equal(t, target, []byte(nil), "MarshalText(key is not assigned) fail")
+}
+
+func TestSetValidNullable(t *testing.T) {
+	target := NullableFromPtr[string](nil)
+	target.SetValid("test")
+	assert.Equal(t, target, NullableFrom("test"), `SetValid("test") fail`)
+}
+
+func TestPtrNullable(t *testing.T) {
+	ptr := NullableFrom("test").Ptr()
+	assert.Equal(t, *ptr, "test", "Ptr() fail")
+	ptr = NullableFromPtr[string](nil).Ptr()
+	assert.Nil(t, ptr, "Ptr() null fail")
+	ptr = Nullable[string]{}.Ptr()
+	assert.Nil(t, ptr, "Ptr() not valid fail")
+}
+
+func TestScanNullable(t *testing.T) {
+	// string
+	s := Nullable[string]{}
+	err := s.Scan("test")
+	checkError(err)
+	assert.Equal(t, s, NullableFrom("test"), `Scan("test") fail`)
+	// []byte into named string type
+	e := Nullable[testEnum]{}
+	err = e.Scan([]byte("a"))
+	checkError(err)
+	assert.Equal(t, e, NullableFrom(testEnum("a")), `Scan([]byte("a")) fail`)
+	// int64 into int32
+	i32 := Nullable[int32]{}
+	err = i32.Scan(int64(12345))
+	checkError(err)
+	assert.Equal(t, i32, NullableFrom(int32(12345)), "Scan(int64) fail")
+	// []byte into int
+	i := Nullable[int]{}
+	err = i.Scan([]byte("12345"))
+	checkError(err)
+	assert.Equal(t, i, NullableFrom(12345), `Scan([]byte("12345")) fail`)
+	// time.Time
+	tm := Nullable[time.Time]{}
+	err = tm.Scan(timeObj)
+	checkError(err)
+	assert.Equal(t, tm, NullableFrom(timeObj), "Scan(time) fail")
+	// sql.Scanner
+	gs := Nullable[String]{}
+	err = gs.Scan("test")
+	checkError(err)
+	assert.Equal(t, gs, NullableFrom(StringFrom("test")), "Scan(sql.Scanner) fail")
+	// nil
+	i = Nullable[int]{}
+	err = i.Scan(nil)
+	checkError(err)
+	assert.Equal(t, i, NullableFromPtr[int](nil), "Scan(nil) fail")
+	// unsupported type
+	i = Nullable[int]{}
+	err = i.Scan(true)
+	assert.Error(t, err, "Scan(true) fail")
+	assert.False(t, i.Valid, "Scan(true) fail")
+}
+
+func TestValueNullable(t *testing.T) {
+	var tests = []struct {
+		param    driver.Valuer
+		expected driver.Value
+	}{
+		{NullableFrom("test"), "test"},
+		{NullableFrom(testEnum("a")), "a"},
+		{NullableFrom(12345), int64(12345)},
+		{NullableFrom(true), true},
+		{NullableFrom(timeObj), timeObj},
+		{NullableFrom(StringFrom("test")), "test"},
+		{NullableFromPtr[int](nil), nil},
+		{Nullable[int]{}, nil},
+	}
+	for _, test := range tests {
+		actual, err := test.param.Value()
+		checkError(err)
+		assert.Equal(t, test.expected, actual, "Expected %v.Value() to be %v, got %v", test.param, test.expected, actual)
+	}
+}
+
+func TestNullableConversion(t *testing.T) {
+	now := time.Now()
+	var tests = []struct {
+		param    interface{}
+		expected interface{}
+	}{
+		{StringFromNullable(StringFrom("test").Nullable()), StringFrom("test")},
+		{IntFromNullable(IntFromPtr(nil).Nullable()), IntFromPtr(nil)},
+		{FloatFromNullable(FloatFrom(1.5).Nullable()), FloatFrom(1.5)},
+		{BoolFromNullable(Bool{}.Nullable()), Bool{}},
+		{TimeFromNullable(TimeFrom(now).Nullable()), TimeFrom(now)},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.param, "Expected %v to be %v", test.param, test.expected)
+	}
+}
+
+func TestValidateNullable(t *testing.T) {
+	t.Parallel()
+
+	type testStructRequiredNullable struct {
+		Kind Nullable[testEnum] `valid:"required"`
+	}
+
+	var tests = []struct {
+		param    testStructRequiredNullable
+		expected bool
+	}{
+		{testStructRequiredNullable{Nullable[testEnum]{}}, false},
+		{testStructRequiredNullable{NullableFromPtr[testEnum](nil)}, false},
+		{testStructRequiredNullable{NullableFrom(testEnum(""))}, true},
+		{testStructRequiredNullable{NullableFrom(testEnum("a"))}, true},
+	}
+	for _, test := range tests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+}
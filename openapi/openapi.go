@@ -0,0 +1,233 @@
+// Package openapi generates OpenAPI 3.1 components.schemas from structs using gomu types.
+//
+// The schemas follow gomu.JSONSchema: properties are named by the json tag, and the valid tag decides
+// whether a property is optional or nullable. A property may be unassigned unless it is listed in
+// required (the required or present tag), and may be null if its type includes "null"
+// (gomu types and pointers, unless the required or notnull tag is set).
+//
+// Generate works on Go values by reflection, while FromPackages works on packages loaded
+// with golang.org/x/tools/go/packages, as the gomu-openapi command does.
+package openapi
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/hapoon/gomu"
+	"golang.org/x/tools/go/packages"
+)
+
+// Version is the OpenAPI version of Document.
+const Version = "3.1.0"
+
+// Document is an OpenAPI document holding only components.
+type Document struct {
+	OpenAPI    string      `json:"openapi"`
+	Info       Info        `json:"info"`
+	Components *Components `json:"components"`
+}
+
+// Info is the info object of an OpenAPI document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components is the components object of an OpenAPI document.
+type Components struct {
+	Schemas map[string]*gomu.Schema `json:"schemas"`
+}
+
+const refPrefix = "#/components/schemas/"
+
+// Generate returns the components holding the schemas of the named struct types of vs,
+// and of the named struct types they refer to.
+func Generate(vs ...interface{}) (*Components, error) {
+	g := gomu.NewSchemaGenerator(refPrefix)
+	for _, v := range vs {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+			return nil, fmt.Errorf("gomu: openapi only accepts named structs; got %v", t)
+		}
+		g.Generate(t)
+	}
+	return &Components{Schemas: g.Defs}, nil
+}
+
+// FromPackages returns the components holding the schemas of the exported named struct types of pkgs,
+// which must be loaded with at least packages.NeedName and packages.NeedTypes.
+// If names is not empty, only the types with those names are generated, besides the types they refer to.
+func FromPackages(pkgs []*packages.Package, names ...string) (*Components, error) {
+	g := &generator{gen: gomu.NewSchemaGenerator(refPrefix)}
+	var errs []string
+	for _, pkg := range pkgs {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("gomu: failed to load packages: %s", strings.Join(errs, "; "))
+	}
+	want := make(map[string]bool, len(names))
+	for _, name := range names {
+		want[name] = true
+	}
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			return nil, fmt.Errorf("gomu: package %s is loaded without types", pkg.PkgPath)
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() || obj.IsAlias() || len(names) > 0 && !want[name] {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+			if _, ok := named.Underlying().(*types.Struct); ok {
+				g.define(named)
+			}
+		}
+	}
+	return &Components{Schemas: g.gen.Defs}, nil
+}
+
+var (
+	gomuPkgPath = reflect.TypeOf(gomu.String{}).PkgPath()
+	gomuTypes   = map[string]reflect.Type{
+		"String": reflect.TypeOf(gomu.String{}),
+		"Int":    reflect.TypeOf(gomu.Int{}),
+		"Float":  reflect.TypeOf(gomu.Float{}),
+		"Bool":   reflect.TypeOf(gomu.Bool{}),
+		"Time":   reflect.TypeOf(gomu.Time{}),
+	}
+)
+
+// generator is the go/types counterpart of gomu.SchemaGenerator, which holds the schemas it defines.
+type generator struct {
+	gen *gomu.SchemaGenerator
+}
+
+func (g *generator) typeSchema(t types.Type) *gomu.Schema {
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		if pkg := obj.Pkg(); pkg != nil {
+			switch {
+			case pkg.Path() == gomuPkgPath && obj.Name() == "Nullable" && t.TypeArgs().Len() == 1:
+				return g.typeSchema(t.TypeArgs().At(0)).Nullable()
+			case pkg.Path() == gomuPkgPath && gomuTypes[obj.Name()] != nil:
+				return g.gen.Generate(gomuTypes[obj.Name()])
+			case pkg.Path() == "time" && obj.Name() == "Time":
+				return &gomu.Schema{Type: gomu.SchemaType{"string"}, Format: "date-time"}
+			case pkg.Path() == "encoding/json" && obj.Name() == "RawMessage":
+				return &gomu.Schema{}
+			}
+		}
+		if _, ok := t.Underlying().(*types.Struct); ok && t.TypeArgs().Len() == 0 {
+			return &gomu.Schema{Ref: refPrefix + g.define(t)}
+		}
+		return g.typeSchema(t.Underlying())
+	case *types.Alias:
+		return g.typeSchema(types.Unalias(t))
+	case *types.Pointer:
+		return g.typeSchema(t.Elem()).Nullable()
+	case *types.Struct:
+		return g.structSchema(t)
+	case *types.Map:
+		return &gomu.Schema{Type: gomu.SchemaType{"object"}, AdditionalProperties: g.typeSchema(t.Elem())}
+	case *types.Slice:
+		return g.listSchema(t.Elem())
+	case *types.Array:
+		return g.listSchema(t.Elem())
+	case *types.Basic:
+		info := t.Info()
+		switch {
+		case info&types.IsString != 0:
+			return &gomu.Schema{Type: gomu.SchemaType{"string"}}
+		case info&types.IsBoolean != 0:
+			return &gomu.Schema{Type: gomu.SchemaType{"boolean"}}
+		case info&types.IsInteger != 0:
+			return &gomu.Schema{Type: gomu.SchemaType{"integer"}}
+		case info&types.IsFloat != 0:
+			return &gomu.Schema{Type: gomu.SchemaType{"number"}}
+		}
+	}
+	return &gomu.Schema{}
+}
+
+func (g *generator) listSchema(elem types.Type) *gomu.Schema {
+	if b, ok := elem.Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+		return &gomu.Schema{Type: gomu.SchemaType{"string"}, ContentEncoding: "base64"}
+	}
+	return &gomu.Schema{Type: gomu.SchemaType{"array"}, Items: g.typeSchema(elem)}
+}
+
+// define adds the schema of the named struct type t to the definitions if it is not there yet, and returns its name.
+func (g *generator) define(t *types.Named) string {
+	obj := t.Obj()
+	return g.gen.Define(obj, obj.Name(), obj.Pkg().Path(), func() *gomu.Schema {
+		return g.structSchema(t.Underlying().(*types.Struct))
+	})
+}
+
+func (g *generator) structSchema(st *types.Struct) *gomu.Schema {
+	s := &gomu.Schema{Type: gomu.SchemaType{"object"}, Properties: make(map[string]*gomu.Schema)}
+	g.addProperties(s, st)
+	return s
+}
+
+func (g *generator) addProperties(s *gomu.Schema, st *types.Struct) {
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, _, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Embedded() && name == "" {
+			ft := f.Type()
+			if p, ok := ft.(*types.Pointer); ok {
+				ft = p.Elem()
+			}
+			if embedded, ok := ft.Underlying().(*types.Struct); ok && !isGomu(ft) {
+				g.addProperties(s, embedded)
+				continue
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name()
+		}
+		ps := g.typeSchema(f.Type())
+		if ps.ApplyValidTag(gomuReflectType(f.Type()), tag.Get("valid")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = ps
+	}
+}
+
+// isGomu reports whether t is one of the gomu types.
+func isGomu(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == gomuPkgPath &&
+		(gomuTypes[named.Obj().Name()] != nil || named.Obj().Name() == "Nullable")
+}
+
+// gomuReflectType returns the reflect.Type of the gomu type t, whose validators are translated by ApplyValidTag,
+// or nil for other types.
+func gomuReflectType(t types.Type) reflect.Type {
+	if !isGomu(t) {
+		return nil
+	}
+	return gomuTypes[t.(*types.Named).Obj().Name()]
+}
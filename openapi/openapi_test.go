@@ -0,0 +1,62 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hapoon/gomu/openapi/testdata/api"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerate(t *testing.T) {
+	c, err := Generate(&api.CreateUserRequest{})
+	assert.NoError(t, err, "Generate fail")
+	data, err := json.Marshal(c)
+	assert.NoError(t, err, "Marshal fail")
+
+	var target struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"schemas"`
+	}
+	err = json.Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Len(t, target.Schemas, 2, "schemas fail")
+
+	user := target.Schemas["CreateUserRequest"]
+	assert.Equal(t, []string{"id", "name"}, user.Required, "required fail")
+	assert.JSONEq(t, `{"type":"integer"}`, string(user.Properties["id"]), "id fail")
+	assert.JSONEq(t, `{"type":["string","null"],"minLength":1,"maxLength":10}`, string(user.Properties["name"]), "name fail")
+	assert.JSONEq(t, `{"type":"string"}`, string(user.Properties["nickname"]), "nickname fail")
+	assert.JSONEq(t, `{"type":["string","null"],"format":"uri"}`, string(user.Properties["homepage"]), "homepage fail")
+	assert.JSONEq(t, `{"type":["integer","null"]}`, string(user.Properties["score"]), "score fail")
+	assert.JSONEq(t, `{"anyOf":[{"$ref":"#/components/schemas/Address"},{"type":"null"}]}`, string(user.Properties["address"]), "address fail")
+	assert.JSONEq(t, `{"type":"array","items":{"type":"string"}}`, string(user.Properties["tags"]), "tags fail")
+	assert.NotContains(t, user.Properties, "internal", "unexported fail")
+	assert.Equal(t, []string{"zip"}, target.Schemas["Address"].Required, "Address required fail")
+
+	_, err = Generate(struct{}{})
+	assert.Error(t, err, "anonymous struct fail")
+}
+
+func TestFromPackages(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedTypes}, "./testdata/api")
+	assert.NoError(t, err, "Load fail")
+
+	c, err := FromPackages(pkgs)
+	assert.NoError(t, err, "FromPackages fail")
+	expect, err := Generate(api.Base{}, api.CreateUserRequest{}, api.Address{})
+	assert.NoError(t, err, "Generate fail")
+	data, err := json.Marshal(c)
+	assert.NoError(t, err, "Marshal fail")
+	expectData, err := json.Marshal(expect)
+	assert.NoError(t, err, "Marshal expect fail")
+	assert.JSONEq(t, string(expectData), string(data), "FromPackages fail")
+
+	c, err = FromPackages(pkgs, "Address")
+	assert.NoError(t, err, "FromPackages(names) fail")
+	assert.Len(t, c.Schemas, 1, "FromPackages(names) schemas fail")
+	assert.Contains(t, c.Schemas, "Address", "FromPackages(names) Address fail")
+}
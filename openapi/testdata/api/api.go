@@ -0,0 +1,24 @@
+package api
+
+import "github.com/hapoon/gomu"
+
+type Base struct {
+	ID gomu.Int `json:"id" valid:"required"`
+}
+
+type CreateUserRequest struct {
+	Base
+	Name     gomu.String        `json:"name" valid:"stringlength(1|10),present"`
+	Nickname gomu.String        `json:"nickname" valid:"notnull"`
+	Homepage gomu.String        `json:"homepage" valid:"url"`
+	Score    gomu.Nullable[int] `json:"score"`
+	Address  *Address           `json:"address"`
+	Tags     []string           `json:"tags"`
+	internal string
+}
+
+type Address struct {
+	Zip gomu.String `json:"zip" valid:"required"`
+}
+
+type unexported struct{}
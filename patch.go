@@ -0,0 +1,169 @@
+package gomu
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ApplyPatch merges patch onto dst following the rules of JSON Merge Patch (RFC 7396).
+// dst must be a non-nil pointer to a struct and patch a struct (or a pointer to one)
+// whose fields are matched with dst's by name.
+//
+// A gomu field that is not Valid in patch, or a nil pointer, leaves dst untouched,
+// a Null one is copied to dst as Null, and any other Valid one replaces dst's value.
+// Nested structs are merged recursively, maps are merged by key (Null entries remove the key),
+// and other fields replace dst's value unless they are the zero value.
+func ApplyPatch(dst, patch interface{}) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gomu: ApplyPatch requires a non-nil pointer to a struct; got %T", dst)
+	}
+	pv := reflect.ValueOf(patch)
+	for pv.Kind() == reflect.Ptr || pv.Kind() == reflect.Interface {
+		if pv.IsNil() {
+			return nil
+		}
+		pv = pv.Elem()
+	}
+	if pv.Kind() != reflect.Struct {
+		return fmt.Errorf("gomu: ApplyPatch only accepts a struct patch; got %s", pv.Kind())
+	}
+	return patcher{}.patchStruct(dv.Elem(), pv)
+}
+
+// patcher merges a patch onto a value.
+// When ignoreNull is true, Null gomu values in the patch are treated as unassigned.
+type patcher struct {
+	ignoreNull bool
+}
+
+func (p patcher) patchStruct(dst, patch reflect.Value) error {
+	t := patch.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		pf := patch.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && !sf.Type.Implements(tristaterType) {
+			if err := p.patchStruct(dst, pf); err != nil {
+				return err
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		df := dst.FieldByName(sf.Name)
+		if !df.IsValid() || !df.CanSet() {
+			continue
+		}
+		if err := p.patchValue(df, pf); err != nil {
+			return Error{Name: sf.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+func (p patcher) patchValue(dst, patch reflect.Value) error {
+	if ts, ok := tristateOf(patch); ok {
+		if null, valid := ts.tristate(); !valid || null && p.ignoreNull {
+			return nil
+		}
+		return patchAssign(dst, patch)
+	}
+	switch patch.Kind() {
+	case reflect.Struct:
+		if isScalarStruct(patch.Type()) || dst.Kind() != reflect.Struct {
+			if patch.IsZero() {
+				return nil
+			}
+			return patchAssign(dst, patch)
+		}
+		return p.patchStruct(dst, patch)
+	case reflect.Ptr:
+		if patch.IsNil() {
+			return nil
+		}
+		if patch.Elem().Kind() == reflect.Struct && !isScalarStruct(patch.Elem().Type()) && dst.Kind() == reflect.Ptr {
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			return p.patchStruct(dst.Elem(), patch.Elem())
+		}
+		return patchAssign(dst, patch)
+	case reflect.Map:
+		if patch.IsNil() {
+			return nil
+		}
+		if dst.Kind() != reflect.Map {
+			return patchAssign(dst, patch)
+		}
+		return p.patchMap(dst, patch)
+	case reflect.Slice, reflect.Interface:
+		if patch.IsNil() {
+			return nil
+		}
+		return patchAssign(dst, patch)
+	default:
+		if patch.IsZero() {
+			return nil
+		}
+		return patchAssign(dst, patch)
+	}
+}
+
+func (p patcher) patchMap(dst, patch reflect.Value) error {
+	if !patch.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("gomu: cannot patch %s with %s", dst.Type(), patch.Type())
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), patch.Len()))
+	}
+	iter := patch.MapRange()
+	for iter.Next() {
+		k, pv := iter.Key(), iter.Value()
+		if isNullPatch(pv) {
+			if !p.ignoreNull {
+				dst.SetMapIndex(k, reflect.Value{})
+			}
+			continue
+		}
+		if IsUnassigned(pv) || pv.Kind() == reflect.Ptr && pv.IsNil() {
+			continue
+		}
+		cur := dst.MapIndex(k)
+		if cur.IsValid() && pv.Kind() == reflect.Struct && !isScalarStruct(pv.Type()) && !pv.Type().Implements(tristaterType) {
+			merged := reflect.New(cur.Type()).Elem()
+			merged.Set(cur)
+			if err := p.patchStruct(merged, pv); err != nil {
+				return err
+			}
+			pv = merged
+		}
+		dst.SetMapIndex(k, pv)
+	}
+	return nil
+}
+
+func patchAssign(dst, patch reflect.Value) error {
+	if !patch.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("gomu: cannot patch %s with %s", dst.Type(), patch.Type())
+	}
+	dst.Set(patch)
+	return nil
+}
+
+// isNullPatch reports whether v removes a map member, which is a nil interface or a Null gomu value.
+func isNullPatch(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return true
+	}
+	if ts, ok := tristateOf(v); ok {
+		null, valid := ts.tristate()
+		return valid && null
+	}
+	return false
+}
+
+// isScalarStruct reports whether structs of type t are encoded as a single value, such as time.Time.
+func isScalarStruct(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
@@ -0,0 +1,120 @@
+package gomu
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructPatchAddress struct {
+	Zip  String `json:"zip"`
+	City String `json:"city"`
+}
+
+type testStructPatch struct {
+	Name      String                  `json:"name"`
+	Age       Int                     `json:"age"`
+	Kind      Nullable[string]        `json:"kind"`
+	Address   testStructPatchAddress  `json:"address"`
+	Billing   *testStructPatchAddress `json:"billing"`
+	Tags      []string                `json:"tags"`
+	Labels    map[string]String       `json:"labels"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+func TestApplyPatch(t *testing.T) {
+	now := time.Now()
+	dst := testStructPatch{
+		Name:    StringFrom("before"),
+		Age:     IntFrom(20),
+		Kind:    NullableFrom("a"),
+		Address: testStructPatchAddress{Zip: StringFrom("100-0001"), City: StringFrom("Tokyo")},
+		Tags:    []string{"x", "y"},
+		Labels:  map[string]String{"env": StringFrom("dev"), "team": StringFrom("core")},
+	}
+	var patch testStructPatch
+	err := json.Unmarshal([]byte(`{
+		"name": "after",
+		"kind": null,
+		"address": {"city": "Osaka"},
+		"billing": {"zip": "530-0001"},
+		"tags": ["z"],
+		"labels": {"env": "prd", "team": null, "owner": "me"}
+	}`), &patch)
+	checkError(err)
+	patch.UpdatedAt = now
+	err = ApplyPatch(&dst, patch)
+	checkError(err)
+	expect := testStructPatch{
+		Name:      StringFrom("after"),
+		Age:       IntFrom(20),
+		Kind:      NullableFromPtr[string](nil),
+		Address:   testStructPatchAddress{Zip: StringFrom("100-0001"), City: StringFrom("Osaka")},
+		Billing:   &testStructPatchAddress{Zip: StringFrom("530-0001")},
+		Tags:      []string{"z"},
+		Labels:    map[string]String{"env": StringFrom("prd"), "owner": StringFrom("me")},
+		UpdatedAt: now,
+	}
+	assert.Equal(t, expect, dst, "ApplyPatch() fail")
+	// empty patch leaves dst untouched
+	err = ApplyPatch(&dst, &testStructPatch{})
+	checkError(err)
+	assert.Equal(t, expect, dst, "ApplyPatch(empty) fail")
+}
+
+func TestApplyPatchPointer(t *testing.T) {
+	type testStructPatchPointer struct {
+		S      *String
+		N      Int
+		Labels map[string]*String
+	}
+	before, unassigned, after := StringFrom("before"), String{}, StringFrom("after")
+	dst := testStructPatchPointer{S: &before, N: IntFrom(1), Labels: map[string]*String{"env": &before}}
+	// nil pointers are unassigned
+	err := ApplyPatch(&dst, testStructPatchPointer{S: nil, N: IntFrom(2), Labels: map[string]*String{"env": nil}})
+	checkError(err)
+	assert.Equal(t, testStructPatchPointer{S: &before, N: IntFrom(2), Labels: map[string]*String{"env": &before}}, dst, "ApplyPatch(nil *String) fail")
+	// pointers to unassigned values are unassigned
+	err = ApplyPatch(&dst, testStructPatchPointer{S: &unassigned})
+	checkError(err)
+	assert.Equal(t, StringFrom("before"), *dst.S, "ApplyPatch(unassigned *String) fail")
+	// pointers to assigned values replace dst's
+	err = ApplyPatch(&dst, testStructPatchPointer{S: &after})
+	checkError(err)
+	assert.Equal(t, StringFrom("after"), *dst.S, "ApplyPatch(*String) fail")
+}
+
+func TestApplyPatchDifferentType(t *testing.T) {
+	type patchStruct struct {
+		Name    String
+		Age     String
+		Unknown String
+	}
+	dst := testStructPatch{Name: StringFrom("before")}
+	err := ApplyPatch(&dst, patchStruct{Name: StringFrom("after"), Unknown: StringFrom("x")})
+	checkError(err)
+	assert.Equal(t, StringFrom("after"), dst.Name, "ApplyPatch(different type) fail")
+	// mismatched field type
+	err = ApplyPatch(&dst, patchStruct{Age: StringFrom("20")})
+	assert.Error(t, err, "ApplyPatch(mismatched type) fail")
+}
+
+func TestApplyPatchInvalidArgs(t *testing.T) {
+	var tests = []struct {
+		dst   interface{}
+		patch interface{}
+	}{
+		{testStructPatch{}, testStructPatch{}},
+		{(*testStructPatch)(nil), testStructPatch{}},
+		{&testStructPatch{}, "patch"},
+	}
+	for _, test := range tests {
+		err := ApplyPatch(test.dst, test.patch)
+		assert.Error(t, err, "Expected ApplyPatch(%T, %T) to fail", test.dst, test.patch)
+	}
+	// nil patch is a no-op
+	err := ApplyPatch(&testStructPatch{}, (*testStructPatch)(nil))
+	assert.NoError(t, err, "ApplyPatch(nil patch) fail")
+}
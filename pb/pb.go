@@ -0,0 +1,205 @@
+// Package pb converts gomu values to and from the Protocol Buffers well-known types
+// of google.golang.org/protobuf.
+//
+// A nil wrapper or Timestamp converts to Null, and both Null and unassigned values convert to nil,
+// since a message field has no other way to tell them apart.
+// FieldMask tells them apart for Update RPCs instead: it lists the fields that are assigned.
+package pb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hapoon/gomu"
+	"github.com/hapoon/gomu/internal/structtag"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// String returns the StringValue of s, or nil if s is null or not valid.
+func String(s gomu.String) *wrapperspb.StringValue {
+	if !s.Valid || s.Null {
+		return nil
+	}
+	return wrapperspb.String(s.String)
+}
+
+// StringFrom creates a new String from v that will be null if v is nil.
+func StringFrom(v *wrapperspb.StringValue) gomu.String {
+	if v == nil {
+		return gomu.NewString("", true, true)
+	}
+	return gomu.StringFrom(v.GetValue())
+}
+
+// Int64 returns the Int64Value of i, or nil if i is null or not valid.
+func Int64(i gomu.Int) *wrapperspb.Int64Value {
+	if !i.Valid || i.Null {
+		return nil
+	}
+	return wrapperspb.Int64(i.Int64)
+}
+
+// IntFrom creates a new Int from v that will be null if v is nil.
+func IntFrom(v *wrapperspb.Int64Value) gomu.Int {
+	if v == nil {
+		return gomu.NewInt(0, true, true)
+	}
+	return gomu.IntFrom(v.GetValue())
+}
+
+// Int32 returns the Int32Value of i, or nil if i is null or not valid.
+// It fails if i overflows int32.
+func Int32(i gomu.Int) (*wrapperspb.Int32Value, error) {
+	if !i.Valid || i.Null {
+		return nil, nil
+	}
+	v := int32(i.Int64)
+	if int64(v) != i.Int64 {
+		return nil, fmt.Errorf("gomu: %d overflows int32", i.Int64)
+	}
+	return wrapperspb.Int32(v), nil
+}
+
+// IntFromInt32 creates a new Int from v that will be null if v is nil.
+func IntFromInt32(v *wrapperspb.Int32Value) gomu.Int {
+	if v == nil {
+		return gomu.NewInt(0, true, true)
+	}
+	return gomu.IntFrom(int64(v.GetValue()))
+}
+
+// Double returns the DoubleValue of f, or nil if f is null or not valid.
+func Double(f gomu.Float) *wrapperspb.DoubleValue {
+	if !f.Valid || f.Null {
+		return nil
+	}
+	return wrapperspb.Double(f.Float64)
+}
+
+// FloatFrom creates a new Float from v that will be null if v is nil.
+func FloatFrom(v *wrapperspb.DoubleValue) gomu.Float {
+	if v == nil {
+		return gomu.NewFloat(0, true, true)
+	}
+	return gomu.FloatFrom(v.GetValue())
+}
+
+// Bool returns the BoolValue of b, or nil if b is null or not valid.
+func Bool(b gomu.Bool) *wrapperspb.BoolValue {
+	if !b.Valid || b.Null {
+		return nil
+	}
+	return wrapperspb.Bool(b.Bool)
+}
+
+// BoolFrom creates a new Bool from v that will be null if v is nil.
+func BoolFrom(v *wrapperspb.BoolValue) gomu.Bool {
+	if v == nil {
+		return gomu.NewBool(false, true, true)
+	}
+	return gomu.BoolFrom(v.GetValue())
+}
+
+// Timestamp returns the Timestamp of t, or nil if t is null or not valid.
+func Timestamp(t gomu.Time) *timestamppb.Timestamp {
+	if !t.Valid || t.Null {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+// TimeFrom creates a new Time from ts that will be null if ts is nil.
+// It fails if ts is out of the range of Timestamp.
+func TimeFrom(ts *timestamppb.Timestamp) (gomu.Time, error) {
+	if ts == nil {
+		return gomu.NewTime(time.Time{}, true, true), nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return gomu.Time{}, err
+	}
+	return gomu.TimeFrom(ts.AsTime()), nil
+}
+
+// FieldMask returns a FieldMask of the gomu fields of struct v that are Valid, including the Null ones,
+// so that an Update RPC sets exactly the fields a PATCH request assigned.
+//
+// The path of a field is the proto field name in its `pb` tag, or its name in snake_case,
+// and `pb:"-"` skips it. The `json` tag is not used, since its names are usually in lowerCamelCase.
+// Nested structs add their paths joined with ".", and embedded structs add their paths as their own.
+// Use the IsValid method of the FieldMask to check the paths against a message.
+func FieldMask(v interface{}) (*fieldmaskpb.FieldMask, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gomu: FieldMask only accepts structs; got %s", val.Kind())
+	}
+	mask := new(fieldmaskpb.FieldMask)
+	appendPaths(mask, val, "")
+	return mask, nil
+}
+
+func appendPaths(mask *fieldmaskpb.FieldMask, v reflect.Value, prefix string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := fieldPath(sf)
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+			ft = ft.Elem()
+		}
+		if gomu.IsType(ft) {
+			if _, valid, _ := gomu.Tristate(fv); sf.PkgPath == "" && valid {
+				mask.Paths = append(mask.Paths, prefix+name)
+			}
+			continue
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		if sf.Anonymous && sf.Tag.Get("pb") == "" {
+			appendPaths(mask, fv, prefix)
+			continue
+		}
+		if sf.PkgPath == "" {
+			appendPaths(mask, fv, prefix+name+".")
+		}
+	}
+}
+
+func fieldPath(sf reflect.StructField) string {
+	if name, _ := structtag.Parse(sf.Tag.Get("pb")); name != "" {
+		return name
+	}
+	return snakeCase(sf.Name)
+}
+
+// snakeCase converts a Go field name such as "DisplayName" or "UserID" to "display_name" or "user_id".
+func snakeCase(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) || i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package pb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWrappers(t *testing.T) {
+	assert.Equal(t, "foo", String(gomu.StringFrom("foo")).GetValue(), "String fail")
+	assert.Nil(t, String(gomu.NewString("", true, true)), "String null fail")
+	assert.Nil(t, String(gomu.String{}), "String not valid fail")
+	assert.Equal(t, gomu.StringFrom("foo"), StringFrom(wrapperspb.String("foo")), "StringFrom fail")
+	assert.Equal(t, gomu.NewString("", true, true), StringFrom(nil), "StringFrom nil fail")
+
+	assert.Equal(t, int64(1), Int64(gomu.IntFrom(1)).GetValue(), "Int64 fail")
+	assert.Nil(t, Int64(gomu.Int{}), "Int64 not valid fail")
+	assert.Equal(t, gomu.IntFrom(1), IntFrom(wrapperspb.Int64(1)), "IntFrom fail")
+	assert.Equal(t, gomu.NewInt(0, true, true), IntFrom(nil), "IntFrom nil fail")
+
+	v32, err := Int32(gomu.IntFrom(2))
+	assert.NoError(t, err, "Int32 fail")
+	assert.Equal(t, int32(2), v32.GetValue(), "Int32 value fail")
+	_, err = Int32(gomu.IntFrom(1 << 40))
+	assert.Error(t, err, "Int32 overflow fail")
+	assert.Equal(t, gomu.IntFrom(2), IntFromInt32(wrapperspb.Int32(2)), "IntFromInt32 fail")
+
+	assert.Equal(t, 1.5, Double(gomu.FloatFrom(1.5)).GetValue(), "Double fail")
+	assert.Equal(t, gomu.NewFloat(0, true, true), FloatFrom(nil), "FloatFrom nil fail")
+
+	assert.Equal(t, true, Bool(gomu.BoolFrom(true)).GetValue(), "Bool fail")
+	assert.Equal(t, gomu.BoolFrom(false), BoolFrom(wrapperspb.Bool(false)), "BoolFrom fail")
+}
+
+func TestTimestamp(t *testing.T) {
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	ts := Timestamp(gomu.TimeFrom(tm))
+	assert.Equal(t, tm, ts.AsTime(), "Timestamp fail")
+	assert.Nil(t, Timestamp(gomu.NewTime(time.Time{}, true, true)), "Timestamp null fail")
+
+	target, err := TimeFrom(ts)
+	assert.NoError(t, err, "TimeFrom fail")
+	assert.Equal(t, gomu.TimeFrom(tm), target, "TimeFrom value fail")
+	target, err = TimeFrom(nil)
+	assert.NoError(t, err, "TimeFrom nil fail")
+	assert.Equal(t, gomu.NewTime(time.Time{}, true, true), target, "TimeFrom nil value fail")
+	_, err = TimeFrom(&timestamppb.Timestamp{Nanos: -1})
+	assert.Error(t, err, "TimeFrom invalid fail")
+}
+
+type testStructPBAudit struct {
+	UpdatedBy gomu.String
+}
+
+type testStructPBAddress struct {
+	City gomu.String `json:"city"`
+	Zip  gomu.String `json:"zip"`
+}
+
+type testStructPB struct {
+	testStructPBAudit
+	DisplayName gomu.String `json:"displayName" pb:"display_name"`
+	UserID      gomu.Int
+	Age         gomu.Int             `json:"age"`
+	LastName    gomu.String          `json:"lastName"`
+	Email       gomu.String          `json:"email"`
+	Address     *testStructPBAddress `json:"address"`
+	Secret      gomu.String          `pb:"-"`
+	Note        string
+}
+
+func TestFieldMask(t *testing.T) {
+	patch := testStructPB{
+		testStructPBAudit: testStructPBAudit{UpdatedBy: gomu.StringFrom("admin")},
+		DisplayName:       gomu.StringFrom("foo"),
+		UserID:            gomu.IntFrom(1),
+		LastName:          gomu.StringFrom("bar"),
+		Email:             gomu.NewString("", true, true),
+		Address:           &testStructPBAddress{Zip: gomu.StringFrom("100")},
+		Secret:            gomu.StringFrom("x"),
+		Note:              "ignored",
+	}
+	mask, err := FieldMask(&patch)
+	assert.NoError(t, err, "FieldMask fail")
+	assert.Equal(t, []string{"updated_by", "display_name", "user_id", "last_name", "email", "address.zip"}, mask.GetPaths(), "FieldMask paths fail")
+
+	mask, err = FieldMask(struct {
+		Seconds gomu.Int
+		Nanos   gomu.Int
+	}{Seconds: gomu.IntFrom(1), Nanos: gomu.NewInt(0, true, true)})
+	assert.NoError(t, err, "FieldMask fail")
+	assert.True(t, mask.IsValid(&timestamppb.Timestamp{}), "FieldMask IsValid fail")
+
+	_, err = FieldMask(1)
+	assert.Error(t, err, "FieldMask non-struct fail")
+}
@@ -0,0 +1,143 @@
+package gomu
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// The valid tags of a struct type are parsed into a structPlan on its first validation,
+// which is cached for the later ones, so that Validate only looks up the validators on each call.
+
+var (
+	structPlans sync.Map // map[reflect.Type]*structPlan
+	tagPlans    sync.Map // map[string]*tagPlan
+)
+
+// structPlan is the validation plan of a struct type.
+type structPlan struct {
+	fields []fieldPlan
+}
+
+// fieldPlan is the validation plan of an exported field.
+type fieldPlan struct {
+	index int
+	name  string // the field name
+	path  string // the json tag name or the field name, or "" for an embedded struct without a json name
+	tag   *tagPlan
+}
+
+// tagPlan is a parsed valid tag.
+type tagPlan struct {
+	tag     string
+	options []*tagOption // in the order of the tag
+
+	omitUnassigned, present, notNull, required *tagOption
+}
+
+// tagOption is an option of a valid tag, such as "required" or "!stringlength(1|10)~invalid name".
+type tagOption struct {
+	key       string // the option without the custom error message
+	message   string // the custom error message
+	validator string // key without "!"
+	negate    bool
+	name      string // the name of the validator, such as "stringlength"
+	params    []string
+
+	paramTag       string // the key of ParamTagRegexMap that the validator matches
+	paramTagParams []string
+}
+
+// structPlanOf returns the cached plan of struct type t, building it on the first call.
+func structPlanOf(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+	p := &structPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		p.fields = append(p.fields, fieldPlan{index: i, name: sf.Name, path: fieldName(sf), tag: tagPlanOf(sf.Tag.Get(tagName))})
+	}
+	actual, _ := structPlans.LoadOrStore(t, p)
+	return actual.(*structPlan)
+}
+
+// resetPlans drops the cached plans, so that the tags are parsed again on their next validation.
+func resetPlans() {
+	structPlans.Clear()
+	tagPlans.Clear()
+}
+
+// fullPath returns the path of the field in the struct at path.
+func (f *fieldPlan) fullPath(path string) string {
+	if f.path == "" {
+		return path
+	}
+	return JoinPath(path, f.path)
+}
+
+// tagPlanOf returns the cached plan of a valid tag, parsing it on the first call.
+func tagPlanOf(tag string) *tagPlan {
+	if p, ok := tagPlans.Load(tag); ok {
+		return p.(*tagPlan)
+	}
+	p := &tagPlan{tag: tag}
+	if tag != "" && tag != "-" {
+		byKey := make(map[string]*tagOption)
+		for _, option := range strings.Split(tag, ",") {
+			validationOptions := strings.Split(option, "~")
+			if !isValidTag(validationOptions[0]) {
+				continue
+			}
+			message := ""
+			if len(validationOptions) == 2 {
+				message = validationOptions[1]
+			}
+			if o, ok := byKey[validationOptions[0]]; ok {
+				o.message = message
+				continue
+			}
+			o := newTagOption(validationOptions[0], message)
+			byKey[o.key] = o
+			p.options = append(p.options, o)
+		}
+		p.omitUnassigned = byKey["omitunassigned"]
+		p.present = byKey["present"]
+		p.notNull = byKey["notnull"]
+		p.required = byKey["required"]
+	}
+	actual, _ := tagPlans.LoadOrStore(tag, p)
+	return actual.(*tagPlan)
+}
+
+func newTagOption(key, message string) *tagOption {
+	o := &tagOption{key: key, message: message, validator: key}
+	if key[0] == '!' {
+		o.validator = key[1:]
+		o.negate = true
+	}
+	o.name, o.params = parseValidatorTag(o.validator)
+	for paramTag, rx := range ParamTagRegexMap {
+		if ps := rx.FindStringSubmatch(o.validator); len(ps) > 0 {
+			o.paramTag, o.paramTagParams = paramTag, ps[1:]
+			break
+		}
+	}
+	return o
+}
+
+// stringValidator looks up the validator in ParamTagMap and TagMap, which only apply to String.
+func (o *tagOption) stringValidator() (TypeValidator, []string, bool) {
+	if validatefunc, ok := ParamTagMap[o.paramTag]; ok && o.paramTag != "" {
+		return stringTypeValidator(validatefunc), o.paramTagParams, true
+	}
+	if validatefunc, ok := TagMap[o.validator]; ok {
+		return stringTypeValidator(func(str string, params ...string) bool {
+			return validatefunc(str)
+		}), nil, true
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,363 @@
+package gomu
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hapoon/gomu/internal/structtag"
+)
+
+// SchemaDraft is the URI of the JSON Schema dialect generated by JSONSchema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Only the keywords that JSONSchema generates are supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 SchemaType         `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// SchemaType is the type keyword of a Schema, which is encoded as a string if it has one type
+// and as an array otherwise.
+type SchemaType []string
+
+// MarshalJSON implements json.Marshaler.
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = SchemaType{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Has reports whether t includes typ.
+func (t SchemaType) Has(typ string) bool {
+	for _, s := range t {
+		if s == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of struct v, which may also be a pointer to a struct.
+//
+// Properties are named by the json tag. gomu types map to their JSON type or null,
+// such as ["string","null"] for String and ["string","null"] with the date-time format for Time.
+// The valid tag is translated as follows, and validators without a JSON Schema equivalent are left out:
+//
+//	required         listed in required, and null is not allowed
+//	present          listed in required
+//	notnull          null is not allowed
+//	stringlength     minLength and maxLength
+//	url, requrl      format uri
+//	requri           format uri-reference
+//	range, min, max  minimum and maximum
+//	positive         exclusiveMinimum 0
+//	in, eq           enum
+//
+// Named struct types other than v are put in $defs and referenced with $ref.
+func JSONSchema(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gomu: JSONSchema only accepts structs; got %v", t)
+	}
+	g := NewSchemaGenerator("#/$defs/")
+	s := g.structSchema(t)
+	s.Schema = SchemaDraft
+	s.Title = t.Name()
+	if len(g.Defs) > 0 {
+		s.Defs = g.Defs
+	}
+	return s, nil
+}
+
+// SchemaGenerator generates the schemas of Go types, collecting named struct types in Defs.
+type SchemaGenerator struct {
+	// Defs holds the schemas of named struct types by name.
+	Defs map[string]*Schema
+
+	refPrefix string
+	names     map[interface{}]string
+}
+
+// NewSchemaGenerator returns a SchemaGenerator that references Defs with refPrefix,
+// such as "#/$defs/" or "#/components/schemas/".
+func NewSchemaGenerator(refPrefix string) *SchemaGenerator {
+	return &SchemaGenerator{
+		Defs:      make(map[string]*Schema),
+		refPrefix: refPrefix,
+		names:     make(map[interface{}]string),
+	}
+}
+
+// Generate returns the schema of t, which is a reference to Defs if t is a named struct type.
+func (g *SchemaGenerator) Generate(t reflect.Type) *Schema {
+	return g.typeSchema(t)
+}
+
+var (
+	stringType     = reflect.TypeOf(String{})
+	intType        = reflect.TypeOf(Int{})
+	floatType      = reflect.TypeOf(Float{})
+	boolType       = reflect.TypeOf(Bool{})
+	timeType       = reflect.TypeOf(Time{})
+	stdTimeType    = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (g *SchemaGenerator) typeSchema(t reflect.Type) *Schema {
+	switch t {
+	case stringType:
+		return &Schema{Type: SchemaType{"string", "null"}}
+	case intType:
+		return &Schema{Type: SchemaType{"integer", "null"}}
+	case floatType:
+		return &Schema{Type: SchemaType{"number", "null"}}
+	case boolType:
+		return &Schema{Type: SchemaType{"boolean", "null"}}
+	case timeType:
+		return &Schema{Type: SchemaType{"string", "null"}, Format: "date-time"}
+	case stdTimeType:
+		return &Schema{Type: SchemaType{"string"}, Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+	if t.Implements(tristaterType) {
+		if f, ok := t.FieldByName("Val"); ok {
+			return g.typeSchema(f.Type).Nullable()
+		}
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem()).Nullable()
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: g.refPrefix + g.define(t)}
+	case reflect.Map:
+		return &Schema{Type: SchemaType{"object"}, AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: SchemaType{"string"}, ContentEncoding: "base64"}
+		}
+		return &Schema{Type: SchemaType{"array"}, Items: g.typeSchema(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: SchemaType{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: SchemaType{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaType{"number"}}
+	}
+	return &Schema{}
+}
+
+// define adds the schema of the named struct type t to Defs if it is not there yet, and returns its name.
+func (g *SchemaGenerator) define(t reflect.Type) string {
+	return g.Define(t, t.Name(), t.PkgPath(), func() *Schema { return g.structSchema(t) })
+}
+
+// Define adds the schema returned by build to Defs as name, unless the type identified by key,
+// such as its reflect.Type, is already defined, and returns the name of its schema.
+// If name is taken by another type, it is prefixed with pkgPath, where "/" is replaced by ".".
+// The name is reserved before build is called, so that recursive types can refer to it.
+func (g *SchemaGenerator) Define(key interface{}, name, pkgPath string, build func() *Schema) string {
+	if name, ok := g.names[key]; ok {
+		return name
+	}
+	if _, taken := g.Defs[name]; taken {
+		name = strings.ReplaceAll(pkgPath, "/", ".") + "." + name
+	}
+	g.names[key] = name
+	g.Defs[name] = nil // reserve the name for recursive types
+	g.Defs[name] = build()
+	return name
+}
+
+// Nullable returns s with null allowed: "null" is added to its type,
+// and a reference is wrapped in anyOf with a null schema since siblings of $ref are ignored.
+func (s *Schema) Nullable() *Schema {
+	switch {
+	case s.Ref != "":
+		return &Schema{AnyOf: []*Schema{s, {Type: SchemaType{"null"}}}}
+	case len(s.Type) == 0 || s.Type.Has("null"):
+		return s
+	}
+	s.Type = append(s.Type, "null")
+	return s
+}
+
+func (g *SchemaGenerator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: SchemaType{"object"}, Properties: make(map[string]*Schema)}
+	g.addProperties(s, t)
+	return s
+}
+
+func (g *SchemaGenerator) addProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _ := structtag.Parse(sf.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !ft.Implements(tristaterType) {
+				g.addProperties(s, ft)
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		ps := g.typeSchema(sf.Type)
+		if ps.ApplyValidTag(sf.Type, sf.Tag.Get(tagName)) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = ps
+	}
+}
+
+// ApplyValidTag translates the valid tag of a field of type t into s as JSONSchema does,
+// and reports whether the field is required.
+func (s *Schema) ApplyValidTag(t reflect.Type, tag string) (required bool) {
+	if tag == "" || tag == "-" {
+		return false
+	}
+	return applyValidTag(s, t, tagPlanOf(tag))
+}
+
+// applyValidTag translates the options of a valid tag into s, and reports whether the property is required.
+func applyValidTag(s *Schema, t reflect.Type, plan *tagPlan) (required bool) {
+	if plan.required != nil || plan.notNull != nil {
+		if len(s.AnyOf) == 2 && s.AnyOf[1].Type.Has("null") {
+			*s = *s.AnyOf[0]
+		}
+		s.Type = removeNull(s.Type)
+	}
+	for _, option := range plan.options {
+		if option.negate {
+			continue
+		}
+		switch t {
+		case stringType:
+			applyStringValidator(s, option.name, option.params)
+		case intType:
+			applyIntValidator(s, option.name, option.params)
+		case boolType:
+			if option.name == "eq" && len(option.params) == 1 {
+				if b, err := strconv.ParseBool(option.params[0]); err == nil {
+					s.Enum = enumOf(s, b)
+				}
+			}
+		}
+	}
+	return plan.required != nil || plan.present != nil
+}
+
+func applyStringValidator(s *Schema, name string, params []string) {
+	switch name {
+	case "stringlength":
+		if len(params) == 2 {
+			if min, err := strconv.Atoi(params[0]); err == nil {
+				s.MinLength = &min
+			}
+			if max, err := strconv.Atoi(params[1]); err == nil {
+				s.MaxLength = &max
+			}
+		}
+	case "url", "requrl":
+		s.Format = "uri"
+	case "requri":
+		s.Format = "uri-reference"
+	}
+}
+
+func applyIntValidator(s *Schema, name string, params []string) {
+	nums := make([]float64, 0, len(params))
+	for _, p := range params {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return
+		}
+		nums = append(nums, float64(n))
+	}
+	switch {
+	case name == "range" && len(nums) == 2:
+		s.Minimum = &nums[0]
+		s.Maximum = &nums[1]
+	case name == "min" && len(nums) == 1:
+		s.Minimum = &nums[0]
+	case name == "max" && len(nums) == 1:
+		s.Maximum = &nums[0]
+	case name == "positive":
+		zero := 0.0
+		s.ExclusiveMinimum = &zero
+	case name == "in" && len(nums) > 0:
+		values := make([]interface{}, len(params))
+		for i, n := range nums {
+			values[i] = int64(n)
+		}
+		s.Enum = enumOf(s, values...)
+	}
+}
+
+// enumOf returns the enum of values, with null added if s allows null.
+func enumOf(s *Schema, values ...interface{}) []interface{} {
+	if s.Type.Has("null") {
+		values = append(values, nil)
+	}
+	return values
+}
+
+func removeNull(t SchemaType) SchemaType {
+	out := make(SchemaType, 0, len(t))
+	for _, s := range t {
+		if s != "null" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
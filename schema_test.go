@@ -0,0 +1,102 @@
+package gomu
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructSchemaAddress struct {
+	Zip  String                   `json:"zip" valid:"stringlength(1|8)"`
+	Next *testStructSchemaAddress `json:"next"`
+}
+
+type testStructSchemaBase struct {
+	ID Int `json:"id" valid:"required"`
+}
+
+type testStructSchema struct {
+	testStructSchemaBase
+	Name    String                  `json:"name" valid:"stringlength(1|10),present"`
+	URL     String                  `json:"url" valid:"requrl"`
+	Age     Int                     `json:"age" valid:"range(0|150)"`
+	Rank    Int                     `json:"rank" valid:"in(1|2|3),notnull"`
+	Active  Bool                    `json:"active" valid:"eq(true)"`
+	Ratio   Float                   `json:"ratio"`
+	Born    Time                    `json:"born"`
+	Score   Nullable[int]           `json:"score"`
+	Tags    []String                `json:"tags"`
+	Address testStructSchemaAddress `json:"address" valid:"required"`
+	Skip    string                  `json:"-"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	s, err := JSONSchema(&testStructSchema{})
+	assert.NoError(t, err, "JSONSchema fail")
+	data, err := json.Marshal(s)
+	assert.NoError(t, err, "Marshal fail")
+
+	var target map[string]interface{}
+	err = json.Unmarshal(data, &target)
+	checkError(err)
+	assert.Equal(t, SchemaDraft, target["$schema"], "$schema fail")
+	assert.Equal(t, "object", target["type"], "type fail")
+	assert.Equal(t, []interface{}{"id", "name", "address"}, target["required"], "required fail")
+
+	props := target["properties"].(map[string]interface{})
+	expect := map[string]interface{}{
+		"id":      map[string]interface{}{"type": "integer"},
+		"name":    map[string]interface{}{"type": []interface{}{"string", "null"}, "minLength": 1.0, "maxLength": 10.0},
+		"url":     map[string]interface{}{"type": []interface{}{"string", "null"}, "format": "uri"},
+		"age":     map[string]interface{}{"type": []interface{}{"integer", "null"}, "minimum": 0.0, "maximum": 150.0},
+		"rank":    map[string]interface{}{"type": "integer", "enum": []interface{}{1.0, 2.0, 3.0}},
+		"active":  map[string]interface{}{"type": []interface{}{"boolean", "null"}, "enum": []interface{}{true, nil}},
+		"ratio":   map[string]interface{}{"type": []interface{}{"number", "null"}},
+		"born":    map[string]interface{}{"type": []interface{}{"string", "null"}, "format": "date-time"},
+		"score":   map[string]interface{}{"type": []interface{}{"integer", "null"}},
+		"tags":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": []interface{}{"string", "null"}}},
+		"address": map[string]interface{}{"$ref": "#/$defs/testStructSchemaAddress"},
+	}
+	assert.Equal(t, expect, props, "properties fail")
+
+	defs := target["$defs"].(map[string]interface{})
+	expect = map[string]interface{}{
+		"testStructSchemaAddress": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"zip": map[string]interface{}{"type": []interface{}{"string", "null"}, "minLength": 1.0, "maxLength": 8.0},
+				"next": map[string]interface{}{"anyOf": []interface{}{
+					map[string]interface{}{"$ref": "#/$defs/testStructSchemaAddress"},
+					map[string]interface{}{"type": "null"},
+				}},
+			},
+		},
+	}
+	assert.Equal(t, expect, defs, "$defs fail")
+
+	_, err = JSONSchema("foo")
+	assert.Error(t, err, "non-struct fail")
+}
+
+func TestSchemaNullable(t *testing.T) {
+	assert.Equal(t, SchemaType{"string", "null"}, (&Schema{Type: SchemaType{"string"}}).Nullable().Type, "Nullable(string) fail")
+	assert.Equal(t, SchemaType{"string", "null"}, (&Schema{Type: SchemaType{"string", "null"}}).Nullable().Type, "Nullable(nullable) fail")
+	assert.Equal(t, &Schema{}, (&Schema{}).Nullable(), "Nullable(any) fail")
+	ref := &Schema{Ref: "#/$defs/T"}
+	assert.Equal(t, &Schema{AnyOf: []*Schema{ref, {Type: SchemaType{"null"}}}}, ref.Nullable(), "Nullable(ref) fail")
+}
+
+func TestSchemaGeneratorDefine(t *testing.T) {
+	g := NewSchemaGenerator("#/$defs/")
+	calls := 0
+	build := func() *Schema {
+		calls++
+		return &Schema{Type: SchemaType{"object"}}
+	}
+	assert.Equal(t, "User", g.Define("a.User", "User", "example.com/a", build), "Define fail")
+	assert.Equal(t, "User", g.Define("a.User", "User", "example.com/a", build), "Define(defined) fail")
+	assert.Equal(t, "example.com.b.User", g.Define("b.User", "User", "example.com/b", build), "Define(taken) fail")
+	assert.Equal(t, 2, calls, "Define build calls fail")
+	assert.Len(t, g.Defs, 2, "Define Defs fail")
+}
@@ -3,6 +3,7 @@ package gomu
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"reflect"
 )
@@ -119,3 +120,49 @@ func (s String) Value() (driver.Value, error) {
 	}
 	return driver.Value(s.String), nil
 }
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+// It will encode null if this String is null or not valid.
+func (s String) MarshalYAML() (interface{}, error) {
+	return s.Nullable().MarshalYAML()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also accepts.
+// Note that both packages leave String unassigned for null instead of calling this method;
+// use github.com/hapoon/gomu/yaml to decode null as Null.
+func (s *String) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return s.updateNullable(func(n *Nullable[string]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// IsZero reports whether this String is not valid, so that the omitempty option of gopkg.in/yaml.v3
+// and the omitzero option of encoding/json omit unassigned values.
+func (s String) IsZero() bool {
+	return s.Nullable().IsZero()
+}
+
+// MarshalXML implements xml.Marshaler.
+// It will encode nothing if this String is not valid, and an element with xsi:nil="true" if it is null.
+func (s String) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return s.Nullable().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler. An element with xsi:nil="true" sets String to null.
+func (s *String) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return s.updateNullable(func(n *Nullable[string]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr.
+// It will encode no attribute if this String is null or not valid.
+func (s String) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return s.Nullable().MarshalXMLAttr(name)
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr.
+func (s *String) UnmarshalXMLAttr(attr xml.Attr) error {
+	return s.updateNullable(func(n *Nullable[string]) error { return n.UnmarshalXMLAttr(attr) })
+}
+
+func (s String) tristate() (null bool, valid bool) {
+	return s.Null, s.Valid
+}
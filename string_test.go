@@ -234,3 +234,27 @@ func TestScanString(t *testing.T) {
 	checkError(err)
 	assert.Equal(t, target, expect, `Scan("test") fail`)
 }
+
+func TestYAMLString(t *testing.T) {
+	// "test"
+	target := String{}
+	err := target.UnmarshalYAML(func(v interface{}) error {
+		*(v.(**string)) = &[]string{"test"}[0]
+		return nil
+	})
+	checkError(err)
+	assert.Equal(t, StringFrom("test"), target, "UnmarshalYAML(\"test\") fail")
+	// null
+	err = target.UnmarshalYAML(func(v interface{}) error { return nil })
+	checkError(err)
+	assert.Equal(t, NewString("", true, true), target, "UnmarshalYAML(null) fail")
+
+	v, err := StringFrom("test").MarshalYAML()
+	checkError(err)
+	assert.Equal(t, "test", v, "MarshalYAML() fail")
+	v, err = NewString("", true, true).MarshalYAML()
+	checkError(err)
+	assert.Nil(t, v, "MarshalYAML() null fail")
+	assert.True(t, String{}.IsZero(), "IsZero() not valid fail")
+	assert.False(t, NewString("", true, true).IsZero(), "IsZero() null fail")
+}
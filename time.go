@@ -3,6 +3,7 @@ package gomu
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"reflect"
 	"time"
@@ -144,3 +145,70 @@ func (t Time) Value() (driver.Value, error) {
 	}
 	return driver.Value(t.Time), nil
 }
+
+// Set implements flag.Value. "" and "null" set Time to null.
+func (t *Time) Set(s string) error {
+	return t.updateNullable(func(n *Nullable[time.Time]) error { return n.Set(s) })
+}
+
+// String implements flag.Value.
+func (t Time) String() string {
+	return t.Nullable().String()
+}
+
+// Get implements flag.Getter. It returns nil if this Time is null or not valid.
+func (t Time) Get() interface{} {
+	return t.Nullable().Get()
+}
+
+// Type returns the name of the value type, as used in the usage message of github.com/spf13/pflag.
+func (t Time) Type() string {
+	return "time"
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+// It will encode null if this Time is null or not valid.
+func (t Time) MarshalYAML() (interface{}, error) {
+	return t.Nullable().MarshalYAML()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 also accepts.
+// Note that both packages leave Time unassigned for null instead of calling this method;
+// use github.com/hapoon/gomu/yaml to decode null as Null.
+func (t *Time) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return t.updateNullable(func(n *Nullable[time.Time]) error { return n.UnmarshalYAML(unmarshal) })
+}
+
+// IsZero reports whether this Time is not valid, so that the omitempty option of gopkg.in/yaml.v3
+// and the omitzero option of encoding/json omit unassigned values.
+func (t Time) IsZero() bool {
+	return t.Nullable().IsZero()
+}
+
+// MarshalXML implements xml.Marshaler.
+// It will encode nothing if this Time is not valid, and an element with xsi:nil="true" if it is null.
+func (t Time) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return t.Nullable().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+// An element with xsi:nil="true" or without content sets Time to null.
+func (t *Time) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return t.updateNullable(func(n *Nullable[time.Time]) error { return n.UnmarshalXML(d, start) })
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr.
+// It will encode no attribute if this Time is null or not valid.
+func (t Time) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return t.Nullable().MarshalXMLAttr(name)
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr. An empty attribute sets Time to null.
+func (t *Time) UnmarshalXMLAttr(attr xml.Attr) error {
+	return t.updateNullable(func(n *Nullable[time.Time]) error { return n.UnmarshalXMLAttr(attr) })
+}
+
+func (t Time) tristate() (null bool, valid bool) {
+	return t.Null, t.Valid
+}
@@ -1,8 +1,10 @@
 package gomu
 
 import (
+	"reflect"
 	"regexp"
 	"sync"
+	"time"
 )
 
 // Validator is a wrapper for functions that return bool and accept string.
@@ -22,7 +24,15 @@ var TagMap = map[string]Validator{
 	"requri": IsRequestURI,
 }
 
-type tagOptionsMap map[string]string
+// TypeValidator is a wrapper for validator functions of a gomu type.
+// The first parameter is the value returned by the type's driver.Valuer (e.g. int64 for Int),
+// and params are the parameters of the tag such as "1" and "10" for "range(1|10)".
+type TypeValidator func(i interface{}, params ...string) bool
+
+// tristater is implemented by every gomu type and reports its Null/Valid pair.
+type tristater interface {
+	tristate() (null bool, valid bool)
+}
 
 // ParamTagMap is a map of functions accept variants parameters.
 var ParamTagMap = map[string]ParamValidator{
@@ -31,11 +41,22 @@ var ParamTagMap = map[string]ParamValidator{
 }
 
 // ParamTagRegexMap maps param tags to their respective regexes.
+// A tag is matched once, on the first validation of the struct type using it,
+// so entries added after that need RegisterParamTag.
 var ParamTagRegexMap = map[string]*regexp.Regexp{
 	"length":       regexp.MustCompile("^length\\((\\d+)\\|(\\d+)\\)$"),
 	"stringlength": regexp.MustCompile("^stringlength\\((\\d+)\\|(\\d+)\\)$"),
 }
 
+// RegisterParamTag sets the validator fn in ParamTagMap and its regex rx in ParamTagRegexMap as name,
+// and drops the cached tags so that they are matched again on their next validation.
+// It must not be called concurrently with validations, like writing to the maps.
+func RegisterParamTag(name string, rx *regexp.Regexp, fn ParamValidator) {
+	ParamTagMap[name] = fn
+	ParamTagRegexMap[name] = rx
+	resetPlans()
+}
+
 type customTypeTagMap struct {
 	validators map[string]CustomTypeValidator
 
@@ -57,3 +78,85 @@ func (tm *customTypeTagMap) Set(name string, ctv CustomTypeValidator) {
 
 // CustomTypeTagMap is a map of functions that can be used as tags for Validate function.
 var CustomTypeTagMap = &customTypeTagMap{validators: make(map[string]CustomTypeValidator)}
+
+type typeTagMap struct {
+	validators map[reflect.Type]map[string]TypeValidator
+
+	sync.RWMutex
+}
+
+func (tm *typeTagMap) Get(t reflect.Type, name string) (TypeValidator, bool) {
+	tm.RLock()
+	defer tm.RUnlock()
+	v, ok := tm.validators[t][name]
+	return v, ok
+}
+
+func (tm *typeTagMap) Set(t reflect.Type, name string, tv TypeValidator) {
+	tm.Lock()
+	defer tm.Unlock()
+	if tm.validators[t] == nil {
+		tm.validators[t] = make(map[string]TypeValidator)
+	}
+	tm.validators[t][name] = tv
+}
+
+func (tm *typeTagMap) has(name string) bool {
+	tm.RLock()
+	defer tm.RUnlock()
+	for _, validators := range tm.validators {
+		if _, ok := validators[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeTagMap is a map of validators for each gomu type, that can be used as tags for Validate function.
+// Validators of String are also looked up in TagMap and ParamTagMap.
+var TypeTagMap = &typeTagMap{validators: map[reflect.Type]map[string]TypeValidator{
+	reflect.TypeOf(Int{}): {
+		"range":    intTypeValidator(IntRange),
+		"min":      intTypeValidator(IntMin),
+		"max":      intTypeValidator(IntMax),
+		"positive": intTypeValidator(func(i int64, params ...string) bool { return IsPositive(i) }),
+		"in":       intTypeValidator(IntIn),
+	},
+	reflect.TypeOf(Time{}): {
+		"before": timeTypeValidator(TimeBefore),
+		"after":  timeTypeValidator(TimeAfter),
+		"past":   timeTypeValidator(func(t time.Time, params ...string) bool { return IsPast(t) }),
+		"future": timeTypeValidator(func(t time.Time, params ...string) bool { return IsFuture(t) }),
+	},
+	reflect.TypeOf(Bool{}): {
+		"eq": boolTypeValidator(BoolEq),
+	},
+}}
+
+func stringTypeValidator(f ParamValidator) TypeValidator {
+	return func(i interface{}, params ...string) bool {
+		s, ok := i.(string)
+		return ok && f(s, params...)
+	}
+}
+
+func intTypeValidator(f func(i int64, params ...string) bool) TypeValidator {
+	return func(i interface{}, params ...string) bool {
+		n, ok := i.(int64)
+		return ok && f(n, params...)
+	}
+}
+
+func timeTypeValidator(f func(t time.Time, params ...string) bool) TypeValidator {
+	return func(i interface{}, params ...string) bool {
+		t, ok := i.(time.Time)
+		return ok && f(t, params...)
+	}
+}
+
+func boolTypeValidator(f func(b bool, params ...string) bool) TypeValidator {
+	return func(i interface{}, params ...string) bool {
+		b, ok := i.(bool)
+		return ok && f(b, params...)
+	}
+}
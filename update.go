@@ -0,0 +1,101 @@
+package gomu
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Placeholder is a style of bind parameters in SQL statements.
+type Placeholder int
+
+const (
+	// Question is the "?" placeholder used by MySQL and SQLite.
+	Question Placeholder = iota
+	// Dollar is the "$1", "$2", ... placeholder used by PostgreSQL.
+	Dollar
+)
+
+// ErrNoAssignedFields is returned by UpdateSet when no field of the struct is assigned.
+var ErrNoAssignedFields = errors.New("gomu: no assigned fields")
+
+// UpdateSet builds the SET clause of an UPDATE statement from the gomu fields of struct s.
+// The column name is taken from the `db` tag, or the field name if there is none, and `db:"-"` skips the field.
+// Fields that are not Valid and nil pointers to gomu types are skipped, Null ones are written as NULL,
+// and the others are bound with their driver.Valuer value in the returned args.
+// Fields that are not gomu types are ignored.
+//
+//	set, args, err := gomu.UpdateSet(req, gomu.Dollar)
+//	// set:  name = $1, age = NULL
+//	db.Exec("UPDATE users SET "+set+" WHERE id = $"+strconv.Itoa(len(args)+1), append(args, id)...)
+func UpdateSet(s interface{}, p Placeholder) (set string, args []interface{}, err error) {
+	val := reflect.ValueOf(s)
+	if val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		err = fmt.Errorf("function only accepts structs; got %s", val.Kind())
+		return
+	}
+	var cols []string
+	if cols, args, err = updateColumns(val, p, cols, args); err != nil {
+		return
+	}
+	if len(cols) == 0 {
+		err = ErrNoAssignedFields
+		return
+	}
+	set = strings.Join(cols, ", ")
+	return
+}
+
+func updateColumns(val reflect.Value, p Placeholder, cols []string, args []interface{}) ([]string, []interface{}, error) {
+	for i := 0; i < val.NumField(); i++ {
+		valueField := val.Field(i)
+		typeField := val.Type().Field(i)
+		column := typeField.Tag.Get("db")
+		if column == "-" {
+			continue
+		}
+		if typeField.Anonymous && typeField.Type.Kind() == reflect.Struct && !typeField.Type.Implements(tristaterType) {
+			var err error
+			if cols, args, err = updateColumns(valueField, p, cols, args); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if typeField.PkgPath != "" {
+			continue
+		}
+		ts, ok := tristateOf(valueField)
+		if !ok {
+			continue
+		}
+		null, valid := ts.tristate()
+		if !valid {
+			continue
+		}
+		if column == "" {
+			column = typeField.Name
+		}
+		if null {
+			cols = append(cols, column+" = NULL")
+			continue
+		}
+		v, err := ts.(driver.Valuer).Value()
+		if err != nil {
+			return nil, nil, Error{Name: typeField.Name, Err: err}
+		}
+		args = append(args, v)
+		switch p {
+		case Dollar:
+			cols = append(cols, column+" = $"+strconv.Itoa(len(args)))
+		default:
+			cols = append(cols, column+" = ?")
+		}
+	}
+	return cols, args, nil
+}
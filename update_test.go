@@ -0,0 +1,62 @@
+package gomu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructUpdateBase struct {
+	UpdatedAt Time `db:"updated_at"`
+}
+
+type testStructUpdate struct {
+	testStructUpdateBase
+	Name    String `db:"name"`
+	Age     Int    `db:"age"`
+	Score   Float
+	Admin   Bool             `db:"admin"`
+	Kind    Nullable[string] `db:"kind"`
+	Ignored String           `db:"-"`
+	Plain   string           `db:"plain"`
+}
+
+func TestUpdateSet(t *testing.T) {
+	now := time.Now()
+	ts := testStructUpdate{
+		testStructUpdateBase: testStructUpdateBase{UpdatedAt: TimeFrom(now)},
+		Name:                 StringFrom("test"),
+		Age:                  IntFromPtr(nil),
+		Score:                FloatFrom(1.5),
+		Kind:                 NullableFrom("a"),
+		Ignored:              StringFrom("ignored"),
+		Plain:                "plain",
+	}
+	// ? placeholder
+	set, args, err := UpdateSet(ts, Question)
+	checkError(err)
+	assert.Equal(t, "updated_at = ?, name = ?, age = NULL, Score = ?, kind = ?", set, "UpdateSet(Question) fail")
+	assert.Equal(t, []interface{}{now, "test", 1.5, "a"}, args, "UpdateSet(Question) fail")
+	// $n placeholder
+	set, args, err = UpdateSet(&ts, Dollar)
+	checkError(err)
+	assert.Equal(t, "updated_at = $1, name = $2, age = NULL, Score = $3, kind = $4", set, "UpdateSet(Dollar) fail")
+	assert.Equal(t, []interface{}{now, "test", 1.5, "a"}, args, "UpdateSet(Dollar) fail")
+	// no assigned fields
+	_, _, err = UpdateSet(testStructUpdate{Plain: "plain"}, Question)
+	assert.Equal(t, ErrNoAssignedFields, err, "UpdateSet(no assigned fields) fail")
+	// pointers to gomu types
+	name := StringFrom("test")
+	set, args, err = UpdateSet(struct {
+		S *String `db:"s"`
+		T *String `db:"t"`
+		N Int     `db:"n"`
+	}{T: &name, N: IntFrom(1)}, Dollar)
+	checkError(err)
+	assert.Equal(t, "t = $1, n = $2", set, "UpdateSet(nil *String) fail")
+	assert.Equal(t, []interface{}{"test", int64(1)}, args, "UpdateSet(nil *String) fail")
+	// not a struct
+	_, _, err = UpdateSet("test", Question)
+	assert.Error(t, err, "UpdateSet(string) fail")
+}
@@ -0,0 +1,302 @@
+package gomu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hapoon/gomu/internal/structtag"
+)
+
+// ValidateJSON validates the JSON object in data against the valid tags of the struct schema,
+// which may also be a pointer to a struct, without decoding data into it.
+// result will be equal to `false` if there are any errors.
+//
+// data is walked as a token stream: keys are matched to fields by the json tag like encoding/json,
+// nested objects and arrays are walked into, and each field value is decoded on its own and checked
+// with the same rules and messages as Validate. A missing key is checked as an unassigned value and
+// a null as a Null value, and a required error tells whether the key is absent or the value is null.
+// The errors are Error with a JSON Pointer as Path and the byte Offset of the value in data.
+// Validators of CustomTypeTagMap receive the zero value of the enclosing struct as the context.
+func ValidateJSON(data []byte, schema interface{}) (result bool, err error) {
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return false, fmt.Errorf("function only accepts structs; got %v", t)
+	}
+	w := &jsonValidator{data: data, dec: json.NewDecoder(bytes.NewReader(data)), result: true}
+	if off := w.next(0); off >= int64(len(data)) || data[off] != '{' {
+		return false, errors.New("gomu: ValidateJSON only accepts a JSON object")
+	}
+	if err = w.object(t, ""); err != nil {
+		return false, err
+	}
+	if _, tokErr := w.dec.Token(); tokErr != io.EOF {
+		if tokErr == nil {
+			tokErr = errors.New("gomu: invalid JSON: data after the top-level value")
+		}
+		return false, tokErr
+	}
+	if len(w.errs) > 0 {
+		err = w.errs
+	}
+	return w.result, err
+}
+
+type jsonValidator struct {
+	data   []byte
+	dec    *json.Decoder
+	result bool
+	errs   Errors
+}
+
+type jsonField struct {
+	name string
+	sf   reflect.StructField
+}
+
+// next returns the offset of the next value from off, skipping whitespace and separators.
+func (w *jsonValidator) next(off int64) int64 {
+	for off < int64(len(w.data)) && strings.IndexByte(" \t\r\n:,", w.data[off]) >= 0 {
+		off++
+	}
+	return off
+}
+
+// peek returns the first byte of the next value and its offset.
+func (w *jsonValidator) peek() (byte, int64) {
+	off := w.next(w.dec.InputOffset())
+	if off >= int64(len(w.data)) {
+		return 0, off
+	}
+	return w.data[off], off
+}
+
+func (w *jsonValidator) object(t reflect.Type, path string) error {
+	if _, err := w.dec.Token(); err != nil {
+		return err
+	}
+	fields := jsonFields(t, nil)
+	seen := make([]bool, len(fields))
+	ctx := reflect.New(t).Elem()
+	for w.dec.More() {
+		tok, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		i := lookupJSONField(fields, tok.(string))
+		if i < 0 {
+			if err = w.dec.Decode(&json.RawMessage{}); err != nil {
+				return err
+			}
+			continue
+		}
+		seen[i] = true
+		if err = w.value(fields[i], ctx, path+"/"+escapeJSONPointer(fields[i].name)); err != nil {
+			return err
+		}
+	}
+	if _, err := w.dec.Token(); err != nil {
+		return err
+	}
+	end := w.dec.InputOffset() - 1
+	for i, f := range fields {
+		if !seen[i] {
+			w.empty(f.sf, ctx, path+"/"+escapeJSONPointer(f.name), end, "the key is absent")
+		}
+	}
+	return nil
+}
+
+func (w *jsonValidator) value(f jsonField, ctx reflect.Value, path string) error {
+	c, off := w.peek()
+	ft := f.sf.Type
+	nested := validatesNested(f.sf)
+	switch {
+	case c == 'n' && nested && isJSONWalkable(ft):
+		if _, err := w.dec.Token(); err != nil {
+			return err
+		}
+		w.empty(f.sf, ctx, path, off, "the value is null")
+		return nil
+	case c == '{' && nested && isJSONWalkableStruct(ft):
+		return w.object(derefType(ft), path)
+	case c == '[' && nested && isJSONWalkableList(ft):
+		n, err := w.list(derefType(ft).Elem(), f.sf.Name, path)
+		if err == nil && n == 0 {
+			w.check(reflect.Zero(ft), f.sf, ctx, path, off, "")
+		}
+		return err
+	}
+	v := reflect.New(ft)
+	if ok, err := w.decode(v, f.sf.Name, path, off); !ok {
+		return err
+	}
+	reason := ""
+	if c == 'n' {
+		reason = "the value is null"
+	}
+	w.check(v.Elem(), f.sf, ctx, path, off, reason)
+	return nil
+}
+
+// list walks into the elements of a JSON array of type t and returns the number of elements.
+// name is the name of the field holding the array, which errors of the elements report.
+func (w *jsonValidator) list(t reflect.Type, name, path string) (n int, err error) {
+	if _, err = w.dec.Token(); err != nil {
+		return
+	}
+	for ; w.dec.More(); n++ {
+		c, off := w.peek()
+		elemPath := path + "/" + strconv.Itoa(n)
+		switch {
+		case c == '{' && isJSONWalkableStruct(t):
+			err = w.object(derefType(t), elemPath)
+		case c == '[' && isJSONWalkableList(t):
+			_, err = w.list(derefType(t).Elem(), name, elemPath)
+		default:
+			_, err = w.decode(reflect.New(t), name, elemPath, off)
+		}
+		if err != nil {
+			return
+		}
+	}
+	_, err = w.dec.Token()
+	return
+}
+
+// decode decodes the next value into v. It reports false if the value does not fit v,
+// and returns an error only if data is not valid JSON.
+func (w *jsonValidator) decode(v reflect.Value, name, path string, off int64) (bool, error) {
+	err := w.dec.Decode(v.Interface())
+	if err == nil {
+		return true, nil
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return false, err
+	}
+	w.result = false
+	w.errs = append(w.errs, Error{Name: name, Path: path, Err: err, Offset: off})
+	return false, nil
+}
+
+// empty checks the field sf whose key is absent or whose value is null.
+// The fields of a struct that is not a pointer are checked as absent keys, like Validate does with its zero value.
+func (w *jsonValidator) empty(sf reflect.StructField, ctx reflect.Value, path string, off int64, reason string) {
+	if t := sf.Type; t.Kind() == reflect.Struct && validatesNested(sf) && isJSONWalkableStruct(t) &&
+		tagPlanOf(sf.Tag.Get(tagName)).required == nil {
+		ctx = reflect.New(t).Elem()
+		for _, f := range jsonFields(t, nil) {
+			w.empty(f.sf, ctx, path+"/"+escapeJSONPointer(f.name), off, "the key is absent")
+		}
+		return
+	}
+	w.check(reflect.Zero(sf.Type), sf, ctx, path, off, reason)
+}
+
+func (w *jsonValidator) check(v reflect.Value, sf reflect.StructField, ctx reflect.Value, path string, off int64, reason string) {
+	result, err := typeCheck(v, sf, ctx, path)
+	w.result = w.result && result
+	if err == nil {
+		return
+	}
+	required := tagPlanOf(sf.Tag.Get(tagName)).required
+	for _, e := range appendErrors(nil, err) {
+		if ve, ok := e.(Error); ok {
+			ve.Offset = off
+			if ve.Validator == "required" && required != nil && required.message == "" && reason != "" {
+				ve.Err = fmt.Errorf("non zero value required, but %s", reason)
+			}
+			e = ve
+		}
+		w.errs = append(w.errs, e)
+	}
+}
+
+// jsonFields returns the fields of struct type t that encoding/json decodes,
+// inlining embedded structs without a json name.
+func jsonFields(t reflect.Type, fields []jsonField) []jsonField {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _ := structtag.Parse(sf.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			if ft := derefType(sf.Type); ft.Kind() == reflect.Struct && !ft.Implements(tristaterType) {
+				fields = jsonFields(ft, fields)
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, jsonField{name: name, sf: sf})
+	}
+	return fields
+}
+
+// lookupJSONField returns the index of the field named key, preferring an exact match like encoding/json.
+func lookupJSONField(fields []jsonField, key string) int {
+	for i, f := range fields {
+		if f.name == key {
+			return i
+		}
+	}
+	for i, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapeJSONPointer(s string) string {
+	return jsonPointerEscaper.Replace(s)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// isJSONWalkableStruct reports whether t is a struct, or a pointer to it, that ValidateJSON walks into
+// instead of decoding, which is the case unless it is a gomu type or decodes itself.
+func isJSONWalkableStruct(t reflect.Type) bool {
+	t = derefType(t)
+	return t.Kind() == reflect.Struct && !t.Implements(tristaterType) &&
+		!reflect.PtrTo(t).Implements(jsonUnmarshalerType) && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// isJSONWalkableList reports whether t is a slice or an array, or a pointer to it, of walkable values.
+func isJSONWalkableList(t reflect.Type) bool {
+	t = derefType(t)
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && isJSONWalkable(t.Elem())
+}
+
+func isJSONWalkable(t reflect.Type) bool {
+	return isJSONWalkableStruct(t) || isJSONWalkableList(t)
+}
+
+// validatesNested reports whether Validate validates the structs in the field sf, which it does if sf has a valid tag.
+func validatesNested(sf reflect.StructField) bool {
+	tag := sf.Tag.Get(tagName)
+	return tag != "" && tag != "-"
+}
@@ -0,0 +1,113 @@
+package gomu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructValidateJSONItem struct {
+	SKU String `json:"sku" valid:"stringlength(1|4)"`
+}
+
+type testStructValidateJSONAddress struct {
+	Zip String `json:"zip" valid:"required"`
+}
+
+type testStructValidateJSON struct {
+	Name     String                         `json:"name" valid:"required"`
+	Nickname String                         `json:"nickname" valid:"present"`
+	Age      Int                            `json:"age" valid:"range(0|150),notnull"`
+	Homepage String                         `json:"homepage" valid:"requrl,omitunassigned"`
+	Address  *testStructValidateJSONAddress `json:"address" valid:"nested"`
+	Items    []testStructValidateJSONItem   `json:"items" valid:"nested"`
+	Tags     []string                       `json:"a/b"`
+}
+
+func TestValidateJSON(t *testing.T) {
+	// valid
+	data := []byte(`{"name":"foo","nickname":null,"age":20,"address":{"zip":"123"},"items":[{"sku":"a"}],"extra":[1,{"x":2}]}`)
+	result, err := ValidateJSON(data, testStructValidateJSON{})
+	assert.Nil(t, err, "ValidateJSON fail")
+	assert.True(t, result, "ValidateJSON fail")
+
+	// absent vs null
+	data = []byte(`{"age":null}`)
+	result, err = ValidateJSON(data, &testStructValidateJSON{})
+	assert.False(t, result, "ValidateJSON(absent) fail")
+	errs := err.(Errors)
+	assert.Len(t, errs, 3, "ValidateJSON(absent) errors fail")
+	assert.Equal(t, Error{Name: "Age", Path: "/age", Validator: "notnull", Err: errs[0].(Error).Err, Offset: 7}, errs[0], "notnull fail")
+	assert.Equal(t, "value must not be null", errs[0].(Error).Err.Error(), "notnull message fail")
+	assert.Equal(t, "/name", errs[1].(Error).Path, "required path fail")
+	assert.Equal(t, "non zero value required, but the key is absent", errs[1].Error(), "required absent fail")
+	assert.Equal(t, int64(11), errs[1].(Error).Offset, "required absent offset fail")
+	assert.Equal(t, "present", errs[2].(Error).Validator, "present fail")
+
+	result, err = ValidateJSON([]byte(`{"name":null,"nickname":"x"}`), testStructValidateJSON{})
+	assert.False(t, result, "ValidateJSON(null) fail")
+	assert.Equal(t, "non zero value required, but the value is null", err.(Errors)[0].Error(), "required null fail")
+	assert.Equal(t, int64(8), err.(Errors)[0].(Error).Offset, "required null offset fail")
+
+	// nested
+	data = []byte(`{"name":"foo","nickname":"x","address":{},` + "\n" + `"items":[{"sku":"a"},{"sku":"abcde"}]}`)
+	result, err = ValidateJSON(data, testStructValidateJSON{})
+	assert.False(t, result, "ValidateJSON(nested) fail")
+	errs = err.(Errors)
+	assert.Len(t, errs, 2, "ValidateJSON(nested) errors fail")
+	assert.Equal(t, "/address/zip", errs[0].(Error).Path, "nested absent path fail")
+	assert.Equal(t, int64(strings.Index(string(data), "}")), errs[0].(Error).Offset, "nested absent offset fail")
+	assert.Equal(t, Error{Name: "SKU", Path: "/items/1/sku", Validator: "stringlength", Params: []string{"1", "4"}, Err: errs[1].(Error).Err, Offset: int64(strings.Index(string(data), `"abcde"`))}, errs[1], "nested stringlength fail")
+	assert.Equal(t, "SKU: abcde does not validate as stringlength(1|4)", errs[1].Error(), "nested message fail")
+
+	// type mismatch and JSON Pointer escaping
+	result, err = ValidateJSON([]byte(`{"name":"foo","nickname":"x","age":"old","a/b":[1]}`), testStructValidateJSON{})
+	assert.False(t, result, "ValidateJSON(type) fail")
+	errs = err.(Errors)
+	assert.Len(t, errs, 2, "ValidateJSON(type) errors fail")
+	assert.Equal(t, "/age", errs[0].(Error).Path, "type path fail")
+	assert.Equal(t, int64(35), errs[0].(Error).Offset, "type offset fail")
+	assert.Equal(t, "/a~1b", errs[1].(Error).Path, "escape fail")
+	// type mismatch of an array element reports the field holding the array
+	_, err = ValidateJSON([]byte(`{"name":"foo","nickname":"x","items":[{"sku":"a"},1]}`), testStructValidateJSON{})
+	errs = err.(Errors)
+	assert.Len(t, errs, 1, "ValidateJSON(element type) errors fail")
+	assert.Equal(t, "Items", errs[0].(Error).Name, "element type name fail")
+	assert.Equal(t, "/items/1", errs[0].(Error).Path, "element type path fail")
+	assert.True(t, strings.HasPrefix(errs[0].Error(), "Items: "), "element type message fail")
+
+	// invalid input
+	_, err = ValidateJSON([]byte(`[]`), testStructValidateJSON{})
+	assert.Error(t, err, "ValidateJSON(array) fail")
+	_, err = ValidateJSON([]byte(`{"name":"foo"`), testStructValidateJSON{})
+	assert.Error(t, err, "ValidateJSON(syntax) fail")
+	_, err = ValidateJSON([]byte(`{} {}`), testStructValidateJSON{})
+	assert.Error(t, err, "ValidateJSON(trailing) fail")
+	_, err = ValidateJSON([]byte(`{}`), "foo")
+	assert.Error(t, err, "ValidateJSON(non-struct) fail")
+}
+
+type testStructValidateJSONNested struct {
+	Home  testStructValidateJSONAddress `json:"home" valid:"nested"`
+	Other testStructValidateJSONAddress `json:"other"`
+}
+
+func TestValidateJSONNested(t *testing.T) {
+	// the zero value of a struct with a valid tag reports its fields like Validate
+	_, expect := Validate(testStructValidateJSONNested{})
+	assert.Equal(t, "home.zip", expect.(Errors)[0].(Error).Path, "Validate(zero) fail")
+	for _, data := range []string{`{}`, `{"home":null}`} {
+		result, err := ValidateJSON([]byte(data), testStructValidateJSONNested{})
+		assert.False(t, result, "ValidateJSON(%s) fail", data)
+		errs := err.(Errors)
+		assert.Len(t, errs, 1, "ValidateJSON(%s) errors fail", data)
+		assert.Equal(t, "/home/zip", errs[0].(Error).Path, "ValidateJSON(%s) path fail", data)
+		assert.Equal(t, "non zero value required, but the key is absent", errs[0].Error(), "ValidateJSON(%s) message fail", data)
+	}
+
+	// a struct without a valid tag is not validated
+	result, err := ValidateJSON([]byte(`{"home":{"zip":"123"},"other":{"zip":null}}`), testStructValidateJSONNested{})
+	checkError(err)
+	assert.True(t, result, "ValidateJSON(untagged) fail")
+}
@@ -1,12 +1,17 @@
 package gomu
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
+
+	"github.com/hapoon/gomu/internal/structtag"
 )
 
 // Validate use tags for fields.
@@ -26,49 +31,129 @@ func Validate(s interface{}) (result bool, err error) {
 		return
 	}
 	var errs Errors
-	for i := 0; i < val.NumField(); i++ {
-		valueField := val.Field(i)
-		typeField := val.Type().Field(i)
-		if typeField.PkgPath != "" {
-			continue
-		}
-		resultField, err2 := typeCheck(valueField, typeField, val)
+	if result, errs = validateStruct(val, "", make(map[visit]bool)); len(errs) > 0 {
+		err = errs
+	}
+	return
+}
+
+func validateStruct(val reflect.Value, path string, visited map[visit]bool) (result bool, errs Errors) {
+	result = true
+	for _, f := range structPlanOf(val.Type()).fields {
+		resultField, err2 := checkField(val.Field(f.index), f.name, f.tag, val, f.fullPath(path), visited)
 		if err2 != nil {
-			errs = append(errs, err2)
+			errs = appendErrors(errs, err2)
 		}
 		result = result && resultField
 	}
-	if len(errs) > 0 {
-		err = errs
-	}
 	return
 }
 
-func typeCheck(v reflect.Value, t reflect.StructField, o reflect.Value) (bool, error) {
+// visit is a pointer or a slice that validateNested is walking into.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// validateNested validates the structs in v, which may be a struct, a pointer to it, or a slice of them.
+// visited holds the pointers and slices on the way to v, so that a cycle of them is walked only once.
+func validateNested(v reflect.Value, path string, visited map[visit]bool) (bool, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		if v.IsNil() || v.Kind() == reflect.Slice && v.Len() == 0 {
+			return true, nil
+		}
+		if visited == nil {
+			visited = make(map[visit]bool)
+		}
+		key := visit{v.Pointer(), v.Type()}
+		if visited[key] {
+			return true, nil
+		}
+		visited[key] = true
+		defer delete(visited, key)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return true, nil
+		}
+		return validateNested(v.Elem(), path, visited)
+	case reflect.Struct:
+		if v.Type().Implements(tristaterType) {
+			return true, nil
+		}
+		result, errs := validateStruct(v, path, visited)
+		if len(errs) > 0 {
+			return result, errs
+		}
+		return result, nil
+	case reflect.Slice, reflect.Array:
+		result := true
+		var errs Errors
+		for i := 0; i < v.Len(); i++ {
+			resultElem, err := validateNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), visited)
+			if err != nil {
+				errs = appendErrors(errs, err)
+			}
+			result = result && resultElem
+		}
+		if len(errs) > 0 {
+			return result, errs
+		}
+		return result, nil
+	}
+	return true, nil
+}
+
+// fieldName returns the name of the field t in paths, which is the json tag name if there is one,
+// or "" for an embedded struct without it.
+func fieldName(t reflect.StructField) string {
+	name, _ := structtag.Parse(t.Tag.Get("json"))
+	if name == "" || name == "-" {
+		if t.Anonymous {
+			return ""
+		}
+		name = t.Name
+	}
+	return name
+}
+
+func typeCheck(v reflect.Value, t reflect.StructField, o reflect.Value, path string) (bool, error) {
+	return checkField(v, t.Name, tagPlanOf(t.Tag.Get(tagName)), o, path, nil)
+}
+
+// checkField validates the value v of the field named name against its parsed valid tag.
+// The structs in a field with a valid tag are validated too; a field without one is skipped.
+func checkField(v reflect.Value, name string, plan *tagPlan, o reflect.Value, path string, visited map[visit]bool) (bool, error) {
 	if !v.IsValid() {
 		return false, nil
 	}
 
-	tag := t.Tag.Get(tagName)
-	switch tag {
-	case "":
-		return true, nil
-	case "-":
+	switch plan.tag {
+	case "", "-":
 		return true, nil
 	}
 
-	options := parseTagIntoMap(tag)
+	var ts tristater
+	if v.Kind() == reflect.Struct && v.Type().Implements(tristaterType) {
+		ts = v.Interface().(tristater)
+		if result, done, err := checkTristate(ts, name, plan, path); done {
+			return result, err
+		}
+	}
+
 	var customTypeErrors Errors
 	var customTypeValidatorsExist bool
-	for validatorName, customErrorMessage := range options {
-		if validatefunc, ok := CustomTypeTagMap.Get(validatorName); ok {
+	for _, option := range plan.options {
+		if validatefunc, ok := CustomTypeTagMap.Get(option.key); ok {
 			customTypeValidatorsExist = true
 			if result := validatefunc(v.Interface(), o.Interface()); !result {
-				if len(customErrorMessage) > 0 {
-					customTypeErrors = append(customTypeErrors, Error{Name: t.Name, Err: fmt.Errorf(customErrorMessage), CustomErrorMessageExists: true})
+				if len(option.message) > 0 {
+					customTypeErrors = append(customTypeErrors, Error{Name: name, Path: path, Validator: option.key, Err: errors.New(option.message), CustomErrorMessageExists: true})
 					continue
 				}
-				customTypeErrors = append(customTypeErrors, Error{Name: t.Name, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v), validatorName), CustomErrorMessageExists: false})
+				customTypeErrors = append(customTypeErrors, Error{Name: name, Path: path, Validator: option.key, Err: fmt.Errorf("%s does not validate as %s", fmt.Sprint(v), option.key), CustomErrorMessageExists: false})
 			}
 		}
 	}
@@ -79,102 +164,82 @@ func typeCheck(v reflect.Value, t reflect.StructField, o reflect.Value) (bool, e
 		return true, nil
 	}
 
-	if isEmptyValue(v) {
-		return checkRequired(v, t, options)
-	}
-
-	switch v.Type() {
-	case reflect.TypeOf(String{}), reflect.TypeOf(Int{}), reflect.TypeOf(Bool{}):
-		for validator, customErrorMessage := range options {
-			var negate bool
-			customMsgExists := (len(customErrorMessage) > 0)
-			if validator[0] == '!' {
-				validator = string(validator[1:])
-				negate = true
-			}
-
-			for key, value := range ParamTagRegexMap {
-				ps := value.FindStringSubmatch(validator)
-				if len(ps) > 0 {
-					if validatefunc, ok := ParamTagMap[key]; ok {
-						switch v.Type() {
-						case reflect.TypeOf(String{}):
-							field := fmt.Sprint(v.FieldByName("String"))
-							if result := validatefunc(field, ps[1:]...); (!result && !negate) || (result && negate) {
-								var err error
-								if !negate {
-									if customMsgExists {
-										err = fmt.Errorf(customErrorMessage)
-									} else {
-										err = fmt.Errorf("%s does not validate as %s", field, validator)
-									}
-								} else {
-									if customMsgExists {
-										err = fmt.Errorf(customErrorMessage)
-									} else {
-										err = fmt.Errorf("%s does validate as %s", field, validator)
-									}
-								}
-								return false, Error{Name: t.Name, Err: err, CustomErrorMessageExists: customMsgExists}
-							}
-						default:
-							return false, Error{t.Name, fmt.Errorf("Validator %s doesn't support type %s", validator, v.Type()), false}
-						}
-					}
-				}
-			}
-
-			if validatefunc, ok := TagMap[validator]; ok {
-				switch v.Type() {
-				case reflect.TypeOf(String{}):
-					field := fmt.Sprint(v.FieldByName("String"))
-					if result := validatefunc(field); !result && !negate || result && negate {
-						var err error
-						if !negate {
-							if customMsgExists {
-								err = fmt.Errorf(customErrorMessage)
-							} else {
-								err = fmt.Errorf("%s does not validate as %s", field, validator)
-							}
-						} else {
-							if customMsgExists {
-								err = fmt.Errorf(customErrorMessage)
-							} else {
-								err = fmt.Errorf("%s does validate as %s", field, validator)
-							}
-						}
-						return false, Error{t.Name, err, customMsgExists}
-					}
-				default:
-					return false, Error{t.Name, fmt.Errorf("Validator %s doesn't support type %s", validator, v.Type()), false}
-				}
+	if ts != nil {
+		if null, valid := ts.tristate(); null || !valid {
+			return checkRequired(name, plan, path)
+		}
+		for _, option := range plan.options {
+			if result, err := checkTypeValidator(ts, v.Type(), name, option, path); !result {
+				return false, err
 			}
 		}
 		return true, nil
 	}
+	if isEmptyValue(v) && (v.Kind() != reflect.Struct || plan.required != nil) {
+		return checkRequired(name, plan, path)
+	}
 	switch v.Kind() {
-	case reflect.Struct:
-		return Validate(v.Interface())
+	case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array:
+		return validateNested(v, path, visited)
 	default:
 		return false, nil
 	}
 }
 
-func parseTagIntoMap(tag string) tagOptionsMap {
-	optionsMap := make(tagOptionsMap)
-	options := strings.SplitN(tag, ",", -1)
-	for _, option := range options {
-		validationOptions := strings.Split(option, "~")
-		if !isValidTag(validationOptions[0]) {
-			continue
-		}
-		if len(validationOptions) == 2 {
-			optionsMap[validationOptions[0]] = validationOptions[1]
-		} else {
-			optionsMap[validationOptions[0]] = ""
+// checkTypeValidator runs the validator of a tag option against the gomu value ts of type t.
+// Options that are not validators, such as "required", are ignored.
+func checkTypeValidator(ts tristater, t reflect.Type, name string, option *tagOption, path string) (bool, error) {
+	customMsgExists := (len(option.message) > 0)
+	validatefunc, ok := TypeTagMap.Get(t, option.name)
+	params := option.params
+	if !ok && t == stringType {
+		validatefunc, params, ok = option.stringValidator()
+	}
+	if !ok {
+		if isValidatorName(option.name) {
+			return false, Error{Name: name, Path: path, Validator: option.name, Params: params, Err: fmt.Errorf("Validator %s doesn't support type %s", option.validator, t)}
 		}
+		return true, nil
+	}
+
+	field, err := ts.(driver.Valuer).Value()
+	if err != nil {
+		return false, Error{Name: name, Path: path, Validator: option.name, Params: params, Err: err}
+	}
+	if result := validatefunc(field, params...); result != option.negate {
+		return true, nil
+	}
+	if customMsgExists {
+		err = errors.New(option.message)
+	} else if !option.negate {
+		err = fmt.Errorf("%s does not validate as %s", fmt.Sprint(field), option.validator)
+	} else {
+		err = fmt.Errorf("%s does validate as %s", fmt.Sprint(field), option.validator)
+	}
+	validator := option.name
+	if option.negate {
+		validator = "!" + validator
+	}
+	return false, Error{Name: name, Path: path, Validator: validator, Params: params, Err: err, CustomErrorMessageExists: customMsgExists}
+}
+
+// parseValidatorTag splits a validator such as "range(1|10)" into its name and parameters.
+func parseValidatorTag(validator string) (name string, params []string) {
+	i := strings.IndexByte(validator, '(')
+	if i <= 0 || !strings.HasSuffix(validator, ")") {
+		return validator, nil
 	}
-	return optionsMap
+	return validator[:i], strings.Split(validator[i+1:len(validator)-1], "|")
+}
+
+func isValidatorName(name string) bool {
+	if _, ok := TagMap[name]; ok {
+		return true
+	}
+	if _, ok := ParamTagMap[name]; ok {
+		return true
+	}
+	return TypeTagMap.has(name)
 }
 
 func isValidTag(s string) bool {
@@ -212,76 +277,46 @@ func isEmptyValue(v reflect.Value) bool {
 	}
 
 	// gomu struct Null check
-	if v.Kind() == reflect.Struct {
-		var s String
-		var i Int
-		var t Time
-		var b Bool
-
-		switch v.Type() {
-		case reflect.TypeOf(s):
-			if result := reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface()); result {
-				return result
-			}
-			rt := reflect.New(reflect.TypeOf(s)).Elem()
-			if f, ok := v.Type().FieldByName("Null"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			if f, ok := v.Type().FieldByName("Valid"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			return reflect.DeepEqual(v.Interface(), rt.Interface())
-
-		case reflect.TypeOf(i):
-			if result := reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface()); result {
-				return result
-			}
-			rt := reflect.New(reflect.TypeOf(i)).Elem()
-			if f, ok := v.Type().FieldByName("Null"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			if f, ok := v.Type().FieldByName("Valid"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			return reflect.DeepEqual(v.Interface(), rt.Interface())
+	if ts, ok := v.Interface().(tristater); ok {
+		null, valid := ts.tristate()
+		return null || !valid
+	}
 
-		case reflect.TypeOf(t):
-			if result := reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface()); result {
-				return result
-			}
-			rt := reflect.New(reflect.TypeOf(t)).Elem()
-			if f, ok := v.Type().FieldByName("Null"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			if f, ok := v.Type().FieldByName("Valid"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			return reflect.DeepEqual(v.Interface(), rt.Interface())
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
 
-		case reflect.TypeOf(b):
-			if result := reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface()); result {
-				return result
-			}
-			rt := reflect.New(reflect.TypeOf(b)).Elem()
-			if f, ok := v.Type().FieldByName("Null"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			if f, ok := v.Type().FieldByName("Valid"); ok {
-				rt.FieldByIndex(f.Index).SetBool(true)
-			}
-			return reflect.DeepEqual(v.Interface(), rt.Interface())
+// checkTristate applies the options that reason about Null and Valid of a gomu value.
+// done is true when the field needs no further validation.
+//
+//	omitunassigned: skip all the other options when the value is not assigned
+//	present:        the value must be assigned, but may be null
+//	notnull:        the value must not be null if it is assigned
+func checkTristate(ts tristater, name string, plan *tagPlan, path string) (result bool, done bool, err error) {
+	null, valid := ts.tristate()
+	if plan.omitUnassigned != nil && !valid {
+		return true, true, nil
+	}
+	if plan.present != nil && !valid {
+		if len(plan.present.message) > 0 {
+			return false, true, Error{Name: name, Path: path, Validator: "present", Err: errors.New(plan.present.message), CustomErrorMessageExists: true}
 		}
+		return false, true, Error{Name: name, Path: path, Validator: "present", Err: fmt.Errorf("value must be present")}
 	}
-
-	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+	if plan.notNull != nil && valid && null {
+		if len(plan.notNull.message) > 0 {
+			return false, true, Error{Name: name, Path: path, Validator: "notnull", Err: errors.New(plan.notNull.message), CustomErrorMessageExists: true}
+		}
+		return false, true, Error{Name: name, Path: path, Validator: "notnull", Err: fmt.Errorf("value must not be null")}
+	}
+	return
 }
 
-func checkRequired(v reflect.Value, t reflect.StructField, options tagOptionsMap) (bool, error) {
-	if requiredOption, isRequired := options["required"]; isRequired {
-		if len(requiredOption) > 0 {
-			return false, Error{t.Name, fmt.Errorf(requiredOption), true}
+func checkRequired(name string, plan *tagPlan, path string) (bool, error) {
+	if plan.required != nil {
+		if len(plan.required.message) > 0 {
+			return false, Error{Name: name, Path: path, Validator: "required", Err: errors.New(plan.required.message), CustomErrorMessageExists: true}
 		}
-		return false, Error{t.Name, fmt.Errorf("non zero value required"), true}
+		return false, Error{Name: name, Path: path, Validator: "required", Err: fmt.Errorf("non zero value required"), CustomErrorMessageExists: true}
 	}
 	return true, nil
 }
@@ -345,3 +380,89 @@ func ByteLength(str string, params ...string) (result bool) {
 	}
 	return
 }
+
+// IntRange check if the integer is between min and max.
+func IntRange(i int64, params ...string) (result bool) {
+	if len(params) == 2 {
+		min, err1 := ToInt(params[0])
+		max, err2 := ToInt(params[1])
+		result = err1 == nil && err2 == nil && i >= min && i <= max
+	}
+	return
+}
+
+// IntMin check if the integer is greater than or equal to min.
+func IntMin(i int64, params ...string) (result bool) {
+	if len(params) == 1 {
+		min, err := ToInt(params[0])
+		result = err == nil && i >= min
+	}
+	return
+}
+
+// IntMax check if the integer is less than or equal to max.
+func IntMax(i int64, params ...string) (result bool) {
+	if len(params) == 1 {
+		max, err := ToInt(params[0])
+		result = err == nil && i <= max
+	}
+	return
+}
+
+// IsPositive check if the integer is greater than zero.
+func IsPositive(i int64) bool {
+	return i > 0
+}
+
+// IntIn check if the integer is one of params.
+func IntIn(i int64, params ...string) bool {
+	for _, param := range params {
+		if n, err := ToInt(param); err == nil && n == i {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeBefore check if the time is before the time of params[0],
+// which is formatted as RFC 3339 or "2006-01-02".
+func TimeBefore(t time.Time, params ...string) (result bool) {
+	if len(params) == 1 {
+		p, err := ToTime(params[0])
+		result = err == nil && t.Before(p)
+	}
+	return
+}
+
+// TimeAfter check if the time is after the time of params[0],
+// which is formatted as RFC 3339 or "2006-01-02".
+func TimeAfter(t time.Time, params ...string) (result bool) {
+	if len(params) == 1 {
+		p, err := ToTime(params[0])
+		result = err == nil && t.After(p)
+	}
+	return
+}
+
+// IsPast check if the time is before now.
+func IsPast(t time.Time) bool {
+	return t.Before(time.Now())
+}
+
+// IsFuture check if the time is after now.
+func IsFuture(t time.Time) bool {
+	return t.After(time.Now())
+}
+
+// BoolEq check if the bool equals params[0], which is "true" or "false".
+func BoolEq(b bool, params ...string) (result bool) {
+	if len(params) == 1 {
+		switch params[0] {
+		case "true":
+			result = b
+		case "false":
+			result = !b
+		}
+	}
+	return
+}
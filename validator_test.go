@@ -1,6 +1,10 @@
 package gomu
 
 import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -301,3 +305,408 @@ func TestValidateGomuTime(t *testing.T) {
 		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
 	}
 }
+
+func TestValidateGomuFloat(t *testing.T) {
+	t.Parallel()
+
+	type testStructGomuFloat struct {
+		Price Float
+	}
+
+	type testStructRequiredGomuFloat struct {
+		Price Float `valid:"required"`
+	}
+
+	// not reuqired Gomu Float
+	gomuFloatTests := []struct {
+		param    testStructGomuFloat
+		expected bool
+	}{
+		{testStructGomuFloat{Float{0, false, false}}, true},
+		{testStructGomuFloat{FloatFrom(1.5)}, true},
+		{testStructGomuFloat{FloatFromPtr(nil)}, true},
+	}
+
+	for _, test := range gomuFloatTests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+
+	// reuqired Gomu Float
+	requiredGomuFloatTests := []struct {
+		param    testStructRequiredGomuFloat
+		expected bool
+	}{
+		{testStructRequiredGomuFloat{Float{0, false, false}}, false},
+		{testStructRequiredGomuFloat{FloatFrom(1.5)}, true},
+		{testStructRequiredGomuFloat{FloatFromPtr(nil)}, false},
+	}
+
+	for _, test := range requiredGomuFloatTests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+}
+
+func TestValidateIntValidators(t *testing.T) {
+	t.Parallel()
+
+	type testStructIntValidators struct {
+		Range    Int `valid:"range(1|10)"`
+		Min      Int `valid:"min(5)"`
+		Max      Int `valid:"max(5)"`
+		Positive Int `valid:"positive"`
+		In       Int `valid:"in(1|3|5)"`
+	}
+
+	var tests = []struct {
+		param    testStructIntValidators
+		expected bool
+	}{
+		{testStructIntValidators{}, true},
+		{testStructIntValidators{Range: IntFrom(1), Min: IntFrom(5), Max: IntFrom(5), Positive: IntFrom(1), In: IntFrom(3)}, true},
+		{testStructIntValidators{Range: IntFrom(11)}, false},
+		{testStructIntValidators{Range: IntFrom(0)}, false},
+		{testStructIntValidators{Min: IntFrom(4)}, false},
+		{testStructIntValidators{Max: IntFrom(6)}, false},
+		{testStructIntValidators{Positive: IntFrom(-1)}, false},
+		{testStructIntValidators{Positive: IntFromPtr(nil)}, true},
+		{testStructIntValidators{In: IntFrom(2)}, false},
+	}
+	for _, test := range tests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+}
+
+func TestValidateTimeValidators(t *testing.T) {
+	t.Parallel()
+
+	type testStructTimeValidators struct {
+		Before Time `valid:"before(2020-01-01T00:00:00Z)"`
+		After  Time `valid:"after(2020-01-01)"`
+		Past   Time `valid:"past"`
+		Future Time `valid:"future"`
+	}
+
+	past := time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)
+	future := time.Now().Add(time.Hour)
+	var tests = []struct {
+		param    testStructTimeValidators
+		expected bool
+	}{
+		{testStructTimeValidators{}, true},
+		{testStructTimeValidators{Before: TimeFrom(past), After: TimeFrom(future), Past: TimeFrom(past), Future: TimeFrom(future)}, true},
+		{testStructTimeValidators{Before: TimeFrom(future)}, false},
+		{testStructTimeValidators{After: TimeFrom(past)}, false},
+		{testStructTimeValidators{Past: TimeFrom(future)}, false},
+		{testStructTimeValidators{Future: TimeFrom(past)}, false},
+	}
+	for _, test := range tests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+}
+
+func TestValidateBoolValidators(t *testing.T) {
+	t.Parallel()
+
+	type testStructBoolValidators struct {
+		Agreed Bool `valid:"eq(true)"`
+	}
+
+	var tests = []struct {
+		param    testStructBoolValidators
+		expected bool
+	}{
+		{testStructBoolValidators{}, true},
+		{testStructBoolValidators{BoolFrom(true)}, true},
+		{testStructBoolValidators{BoolFrom(false)}, false},
+	}
+	for _, test := range tests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+}
+
+func TestValidateTypeValidatorMessages(t *testing.T) {
+	type testStructMessages struct {
+		Age    Int    `valid:"range(1|10)"`
+		Name   String `valid:"positive"`
+		Custom Int    `valid:"min(5)~too small"`
+		Negate Int    `valid:"!in(1|2)"`
+	}
+
+	_, err := Validate(testStructMessages{Age: IntFrom(11)})
+	assert.EqualError(t, err, "Age: 11 does not validate as range(1|10);")
+	_, err = Validate(testStructMessages{Name: StringFrom("test")})
+	assert.EqualError(t, err, "Name: Validator positive doesn't support type gomu.String;")
+	_, err = Validate(testStructMessages{Custom: IntFrom(1)})
+	assert.EqualError(t, err, "too small;")
+	_, err = Validate(testStructMessages{Negate: IntFrom(1)})
+	assert.EqualError(t, err, "Negate: 1 does validate as in(1|2);")
+}
+
+func TestTypeTagMap(t *testing.T) {
+	type testStructEven struct {
+		Count Float `valid:"even"`
+	}
+
+	TypeTagMap.Set(reflect.TypeOf(Float{}), "even", func(i interface{}, params ...string) bool {
+		return int64(i.(float64))%2 == 0
+	})
+
+	result, err := Validate(testStructEven{FloatFrom(2)})
+	ignoreError(err)
+	assert.True(t, result, "Validate(even) fail")
+	result, err = Validate(testStructEven{FloatFrom(3)})
+	ignoreError(err)
+	assert.False(t, result, "Validate(even) fail")
+}
+
+func TestValidateTristateTags(t *testing.T) {
+	t.Parallel()
+
+	type testStructTristate struct {
+		Present  String `valid:"present"`
+		NotNull  String `valid:"notnull"`
+		Optional String `valid:"omitunassigned,required,stringlength(1|3)"`
+	}
+
+	var tests = []struct {
+		param    testStructTristate
+		expected bool
+	}{
+		{testStructTristate{Present: StringFrom("a")}, true},
+		{testStructTristate{Present: StringFromPtr(nil)}, true},
+		{testStructTristate{}, false},
+		{testStructTristate{Present: StringFrom("a"), NotNull: StringFrom("a")}, true},
+		{testStructTristate{Present: StringFrom("a"), NotNull: StringFromPtr(nil)}, false},
+		{testStructTristate{Present: StringFrom("a"), Optional: StringFrom("abc")}, true},
+		{testStructTristate{Present: StringFrom("a"), Optional: StringFrom("abcd")}, false},
+		{testStructTristate{Present: StringFrom("a"), Optional: StringFromPtr(nil)}, false},
+	}
+	for _, test := range tests {
+		actual, err := Validate(test.param)
+		ignoreError(err)
+		assert.Equal(t, test.expected, actual, "Expected Validate(%+v) to be %v, got %v", test.param, test.expected, actual)
+	}
+
+	type testStructTristateMessages struct {
+		Present String `valid:"present~name is missing"`
+		NotNull Int    `valid:"notnull"`
+	}
+
+	_, err := Validate(testStructTristateMessages{NotNull: IntFromPtr(nil)})
+	assert.EqualError(t, err, "name is missing;NotNull: value must not be null;")
+}
+
+func TestValidateErrorPath(t *testing.T) {
+	type testStructPathItem struct {
+		SKU String `json:"sku" valid:"required"`
+	}
+
+	type testStructPathAddress struct {
+		Zip String `json:"zip" valid:"stringlength(1|8)"`
+	}
+
+	type testStructPath struct {
+		Name    String                 `valid:"required"`
+		Address testStructPathAddress  `json:"address" valid:"nested"`
+		Items   []testStructPathItem   `json:"items" valid:"nested"`
+		Billing *testStructPathAddress `json:"billing,omitempty" valid:"nested"`
+	}
+
+	test := testStructPath{
+		Name:    StringFrom("test"),
+		Address: testStructPathAddress{Zip: StringFrom("123456789")},
+		Items:   []testStructPathItem{{SKU: StringFrom("a")}, {SKU: StringFrom("b")}, {}},
+		Billing: &testStructPathAddress{Zip: StringFrom("12345678")},
+	}
+	result, err := Validate(test)
+	assert.False(t, result, "Validate(nested) fail")
+	errs, ok := err.(Errors)
+	assert.True(t, ok, "Validate(nested) should return Errors")
+	assert.Len(t, errs, 2, "Validate(nested) fail")
+
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.(Error).Path)
+	}
+	assert.Equal(t, []string{"address.zip", "items[2].sku"}, paths, "Validate(nested) paths fail")
+	assert.Equal(t, "stringlength", errs[0].(Error).Validator, "Validate(nested) validator fail")
+	assert.Equal(t, []string{"1", "8"}, errs[0].(Error).Params, "Validate(nested) params fail")
+	assert.Equal(t, "required", errs[1].(Error).Validator, "Validate(nested) validator fail")
+
+	// nil pointer is not validated
+	test.Address = testStructPathAddress{}
+	test.Items = nil
+	test.Billing = nil
+	result, err = Validate(test)
+	ignoreError(err)
+	assert.True(t, result, "Validate(nil pointer) fail")
+}
+
+func TestValidateNested(t *testing.T) {
+	type testStructNestedInner struct {
+		Zip String `json:"zip" valid:"required"`
+	}
+
+	type testStructNestedUntagged struct {
+		Inner testStructNestedInner `json:"inner"`
+	}
+
+	type testStructNestedTagged struct {
+		Inner testStructNestedInner `json:"inner" valid:"nested"`
+	}
+
+	// a struct field without a valid tag is not validated
+	result, err := Validate(testStructNestedUntagged{})
+	checkError(err)
+	assert.True(t, result, "Validate(untagged) fail")
+
+	// the zero value of a struct field with a valid tag is validated
+	result, err = Validate(testStructNestedTagged{})
+	assert.False(t, result, "Validate(tagged) fail")
+	assert.EqualError(t, err, "non zero value required;", "Validate(tagged) fail")
+	assert.Equal(t, "inner.zip", err.(Errors)[0].(Error).Path, "Validate(tagged) path fail")
+}
+
+type testStructCycle struct {
+	Name     String             `json:"name" valid:"required"`
+	Next     *testStructCycle   `json:"next" valid:"nested"`
+	Children []*testStructCycle `json:"children" valid:"nested"`
+}
+
+func TestValidateCycle(t *testing.T) {
+	n := &testStructCycle{}
+	n.Next = n
+	n.Children = []*testStructCycle{n, {Name: StringFrom("child")}}
+	result, err := Validate(n)
+	assert.False(t, result, "Validate(cycle) fail")
+	var paths []string
+	for _, e := range err.(Errors) {
+		paths = append(paths, e.(Error).Path)
+	}
+	assert.Equal(t, []string{"name", "next.name", "children[0].name"}, paths, "Validate(cycle) paths fail")
+}
+
+func TestValidateTagOrder(t *testing.T) {
+	type testStructTagOrder struct {
+		Name String `valid:"stringlength(5|10),length(1|2)"`
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err := Validate(testStructTagOrder{StringFrom("abc")})
+		assert.EqualError(t, err, "Name: abc does not validate as stringlength(5|10);", "Validate(tag order) fail")
+	}
+}
+
+func TestRegisterParamTag(t *testing.T) {
+	type testStructParamTag struct {
+		Code String `valid:"digits(3)"`
+	}
+	defer func() {
+		delete(ParamTagMap, "digits")
+		delete(ParamTagRegexMap, "digits")
+		resetPlans()
+	}()
+
+	// an unknown validator is ignored
+	result, err := Validate(testStructParamTag{StringFrom("12")})
+	checkError(err)
+	assert.True(t, result, "Validate(unregistered) fail")
+
+	RegisterParamTag("digits", regexp.MustCompile(`^digits\((\d+)\)$`), func(str string, params ...string) bool {
+		n, _ := strconv.Atoi(params[0])
+		return len(str) == n
+	})
+	result, err = Validate(testStructParamTag{StringFrom("12")})
+	assert.False(t, result, "Validate(registered) fail")
+	assert.EqualError(t, err, "Code: 12 does not validate as digits(3);", "Validate(registered) fail")
+	result, err = Validate(testStructParamTag{StringFrom("123")})
+	checkError(err)
+	assert.True(t, result, "Validate(registered) fail")
+}
+
+func TestValidateConcurrent(t *testing.T) {
+	type testStructConcurrent struct {
+		Name String `json:"name" valid:"required,stringlength(1|5)"`
+		Age  Int    `json:"age" valid:"range(0|150)"`
+	}
+
+	var wg sync.WaitGroup
+	results := make([]error, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i] = Validate(testStructConcurrent{Name: StringFrom("too long"), Age: IntFrom(200)})
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range results {
+		assert.EqualError(t, err, "Name: too long does not validate as stringlength(1|5);Age: 200 does not validate as range(0|150);", "Validate(concurrent) fail")
+	}
+}
+
+type testStructBenchmarkValidate struct {
+	Name      String `json:"name" valid:"required,stringlength(1|20)"`
+	Nickname  String `json:"nickname" valid:"present"`
+	Email     String `json:"email" valid:"length(3|254)"`
+	Homepage  String `json:"homepage" valid:"requrl,omitunassigned"`
+	Avatar    String `json:"avatar" valid:"requri"`
+	Blog      String `json:"blog" valid:"url"`
+	Code      String `json:"code" valid:"!length(3|3)"`
+	Note      String `json:"note" valid:"notnull,stringlength(0|100)"`
+	Age       Int    `json:"age" valid:"range(0|150)"`
+	Rank      Int    `json:"rank" valid:"in(1|2|3)"`
+	Count     Int    `json:"count" valid:"positive"`
+	Min       Int    `json:"min" valid:"min(1)"`
+	Max       Int    `json:"max" valid:"max(10)"`
+	Level     Int    `json:"level" valid:"required,range(1|10)"`
+	Agreed    Bool   `json:"agreed" valid:"eq(true)"`
+	Active    Bool   `json:"active" valid:"notnull"`
+	Born      Time   `json:"born" valid:"past"`
+	Expires   Time   `json:"expires" valid:"after(2020-01-01)"`
+	CreatedAt Time   `json:"created_at" valid:"before(2100-01-01T00:00:00Z)"`
+	Ratio     Float  `json:"ratio"`
+}
+
+func BenchmarkValidate(b *testing.B) {
+	test := testStructBenchmarkValidate{
+		Name:      StringFrom("gomu"),
+		Nickname:  NewString("", true, true),
+		Email:     StringFrom("gomu@example.com"),
+		Homepage:  StringFrom("https://example.com/"),
+		Avatar:    StringFrom("/avatar.png"),
+		Blog:      StringFrom("https://blog.example.com/"),
+		Code:      StringFrom("abcd"),
+		Note:      StringFrom("note"),
+		Age:       IntFrom(20),
+		Rank:      IntFrom(2),
+		Count:     IntFrom(3),
+		Min:       IntFrom(1),
+		Max:       IntFrom(10),
+		Level:     IntFrom(5),
+		Agreed:    BoolFrom(true),
+		Active:    BoolFrom(false),
+		Born:      TimeFrom(time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)),
+		Expires:   TimeFrom(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)),
+		CreatedAt: TimeFrom(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+		Ratio:     FloatFrom(0.5),
+	}
+	if result, err := Validate(test); !result || err != nil {
+		b.Fatalf("Validate fail: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Validate(test)
+	}
+}
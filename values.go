@@ -0,0 +1,305 @@
+package gomu
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hapoon/gomu/internal/structtag"
+)
+
+// DecodeValues fills the struct pointed to by dst from values, such as a parsed query string or form.
+// See ValuesDecoder for the rules.
+func DecodeValues(values url.Values, dst interface{}) error {
+	return (&ValuesDecoder{}).Decode(values, dst)
+}
+
+// ValuesDecoder fills structs from url.Values.
+//
+// The key of a field is the name in its `form` tag, `query` tag or `json` tag, or the field name,
+// and `form:"-"` skips the field. Nested structs are filled from dotted keys such as "address.zip",
+// and slices from repeated keys.
+//
+// A gomu field whose key is missing is left unassigned (Valid is false),
+// one whose value is "" or "null" is set to Null, and any other value is decoded with UnmarshalText.
+// Fields of other types are decoded with UnmarshalText if they implement encoding.TextUnmarshaler,
+// or parsed if they are strings, bools or numbers.
+type ValuesDecoder struct {
+	// DisallowUnknownKeys makes Decode fail when values has a key that does not match any field.
+	DisallowUnknownKeys bool
+}
+
+// Decode fills the struct pointed to by dst from values.
+func (d *ValuesDecoder) Decode(values url.Values, dst interface{}) error {
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gomu: DecodeValues requires a non-nil pointer to a struct; got %T", dst)
+	}
+	used := make(map[string]bool)
+	if err := decodeStructValues(values, val.Elem(), "", used); err != nil {
+		return err
+	}
+	if d.DisallowUnknownKeys {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !used[key] {
+				return fmt.Errorf("gomu: unknown key %q", key)
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeValues returns the url.Values of struct src, which is the inverse of DecodeValues.
+// Unassigned gomu fields are omitted, Null ones are encoded as "null",
+// and the others are encoded with MarshalText.
+func EncodeValues(src interface{}) (url.Values, error) {
+	val := reflect.ValueOf(src)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gomu: EncodeValues only accepts structs; got %s", val.Kind())
+	}
+	values := make(url.Values)
+	if err := encodeStructValues(values, val, ""); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// valuesKey returns the key of the field t, and whether the key is taken from a tag.
+func valuesKey(t reflect.StructField) (string, bool) {
+	for _, tag := range []string{"form", "query", "json"} {
+		if name, _ := structtag.Parse(t.Tag.Get(tag)); name != "" {
+			return name, true
+		}
+	}
+	return t.Name, false
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// isTextType reports whether values of type t are decoded from a single string.
+func isTextType(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func decodeStructValues(values url.Values, v reflect.Value, prefix string, used map[string]bool) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, tagged := valuesKey(sf)
+		if key == "-" {
+			continue
+		}
+		if sf.Anonymous && !tagged && sf.Type.Kind() == reflect.Struct && !isTextType(sf.Type) {
+			if err := decodeStructValues(values, v.Field(i), prefix, used); err != nil {
+				return err
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if err := decodeFieldValues(values, v.Field(i), joinKey(prefix, key), used); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeFieldValues(values url.Values, v reflect.Value, key string, used map[string]bool) error {
+	if isTextType(v.Type()) {
+		vs := values[key]
+		if len(vs) == 0 {
+			return nil
+		}
+		used[key] = true
+		return decodeText(v, vs[0], key)
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		vs := values[key]
+		if len(vs) == 0 || !isTextType(v.Type().Elem()) {
+			return nil
+		}
+		used[key] = true
+		s := reflect.MakeSlice(v.Type(), len(vs), len(vs))
+		for i, str := range vs {
+			if err := decodeText(s.Index(i), str, key); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Struct:
+		return decodeStructValues(values, v, key, used)
+	case reflect.Ptr:
+		et := v.Type().Elem()
+		if isTextType(et) {
+			vs := values[key]
+			if len(vs) == 0 {
+				return nil
+			}
+			used[key] = true
+			if v.IsNil() {
+				v.Set(reflect.New(et))
+			}
+			return decodeText(v.Elem(), vs[0], key)
+		}
+		if et.Kind() != reflect.Struct || !hasKeyPrefix(values, key+".") {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(et))
+		}
+		return decodeStructValues(values, v.Elem(), key, used)
+	}
+	return nil
+}
+
+func hasKeyPrefix(values url.Values, prefix string) bool {
+	for key := range values {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeText(v reflect.Value, str string, key string) (err error) {
+	if _, ok := v.Interface().(tristater); ok && (str == "" || str == "null") {
+		setNull(v)
+		return
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		err = u.UnmarshalText([]byte(str))
+	} else {
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString(str)
+		case reflect.Bool:
+			var b bool
+			b, err = strconv.ParseBool(str)
+			v.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var n int64
+			n, err = strconv.ParseInt(str, 10, v.Type().Bits())
+			v.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			var n uint64
+			n, err = strconv.ParseUint(str, 10, v.Type().Bits())
+			v.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			f, err = strconv.ParseFloat(str, v.Type().Bits())
+			v.SetFloat(f)
+		}
+	}
+	if err != nil {
+		return Error{Name: key, Path: key, Err: err}
+	}
+	return
+}
+
+// setNull sets the gomu value v to Null.
+func setNull(v reflect.Value) {
+	v.Set(reflect.Zero(v.Type()))
+	v.FieldByName("Null").SetBool(true)
+	v.FieldByName("Valid").SetBool(true)
+}
+
+func encodeStructValues(values url.Values, v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, tagged := valuesKey(sf)
+		if key == "-" {
+			continue
+		}
+		if sf.Anonymous && !tagged && sf.Type.Kind() == reflect.Struct && !isTextType(sf.Type) {
+			if err := encodeStructValues(values, v.Field(i), prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if err := encodeFieldValues(values, v.Field(i), joinKey(prefix, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeFieldValues(values url.Values, v reflect.Value, key string) error {
+	if isTextType(v.Type()) {
+		return encodeText(values, v, key)
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !isTextType(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeText(values, v.Index(i), key); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return encodeStructValues(values, v, key)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeFieldValues(values, v.Elem(), key)
+	}
+	return nil
+}
+
+func encodeText(values url.Values, v reflect.Value, key string) error {
+	if IsUnassigned(v) {
+		return nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return Error{Name: key, Path: key, Err: err}
+		}
+		values.Add(key, string(text))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		values.Add(key, strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()))
+	default:
+		values.Add(key, fmt.Sprint(v.Interface()))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package gomu
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructValuesAddress struct {
+	Zip  String `form:"zip"`
+	City String `form:"city"`
+}
+
+type testStructValues struct {
+	Name    String                   `form:"name"`
+	Age     Int                      `query:"age"`
+	Admin   Bool                     `json:"admin"`
+	Score   Float                    `form:"score"`
+	Since   Time                     `form:"since"`
+	Tags    []String                 `form:"tag"`
+	IDs     []int                    `form:"id"`
+	Page    int                      `form:"page"`
+	Address testStructValuesAddress  `form:"address"`
+	Billing *testStructValuesAddress `form:"billing"`
+	Ignored String                   `form:"-"`
+}
+
+func TestDecodeValues(t *testing.T) {
+	values, err := url.ParseQuery("name=test&age=&admin=null&score=1.5&since=" + url.QueryEscape(timeString) +
+		"&tag=a&tag=null&id=1&id=2&page=3&address.zip=100-0001&Ignored=x")
+	checkError(err)
+	var target testStructValues
+	err = DecodeValues(values, &target)
+	checkError(err)
+	expect := testStructValues{
+		Name:    StringFrom("test"),
+		Age:     IntFromPtr(nil),
+		Admin:   BoolFromPtr(nil),
+		Score:   FloatFrom(1.5),
+		Since:   TimeFrom(timeObj),
+		Tags:    []String{StringFrom("a"), StringFromPtr(nil)},
+		IDs:     []int{1, 2},
+		Page:    3,
+		Address: testStructValuesAddress{Zip: StringFrom("100-0001")},
+	}
+	assert.Equal(t, expect, target, "DecodeValues() fail")
+	// nested pointer is allocated only when a key is present
+	target = testStructValues{}
+	err = DecodeValues(url.Values{"billing.city": {"Osaka"}}, &target)
+	checkError(err)
+	assert.Equal(t, &testStructValuesAddress{City: StringFrom("Osaka")}, target.Billing, "DecodeValues(pointer) fail")
+	// invalid value
+	target = testStructValues{}
+	err = DecodeValues(url.Values{"age": {"abc"}}, &target)
+	if assert.Error(t, err, "DecodeValues(invalid) fail") {
+		assert.Equal(t, "age", err.(Error).Path, "DecodeValues(invalid) path fail")
+	}
+	// not a pointer
+	err = DecodeValues(values, target)
+	assert.Error(t, err, "DecodeValues(not pointer) fail")
+}
+
+func TestValuesDecoderDisallowUnknownKeys(t *testing.T) {
+	d := &ValuesDecoder{DisallowUnknownKeys: true}
+	var target testStructValues
+	err := d.Decode(url.Values{"name": {"test"}, "address.zip": {"1"}}, &target)
+	assert.NoError(t, err, "Decode(known keys) fail")
+	err = d.Decode(url.Values{"name": {"test"}, "unknown": {"1"}}, &target)
+	assert.EqualError(t, err, `gomu: unknown key "unknown"`, "Decode(unknown key) fail")
+}
+
+func TestEncodeValues(t *testing.T) {
+	src := testStructValues{
+		Name:    StringFrom("test"),
+		Age:     IntFromPtr(nil),
+		Score:   FloatFrom(1.5),
+		Since:   TimeFrom(time.Date(2016, 10, 6, 10, 0, 0, 0, time.UTC)),
+		Tags:    []String{StringFrom("a"), {}, StringFromPtr(nil)},
+		IDs:     []int{1, 2},
+		Address: testStructValuesAddress{City: StringFrom("Tokyo")},
+		Billing: &testStructValuesAddress{Zip: StringFrom("530-0001")},
+		Ignored: StringFrom("x"),
+	}
+	values, err := EncodeValues(src)
+	checkError(err)
+	expect := url.Values{
+		"name":         {"test"},
+		"age":          {"null"},
+		"score":        {"1.5"},
+		"since":        {timeString},
+		"tag":          {"a", "null"},
+		"id":           {"1", "2"},
+		"page":         {"0"},
+		"address.city": {"Tokyo"},
+		"billing.zip":  {"530-0001"},
+	}
+	assert.Equal(t, expect, values, "EncodeValues() fail")
+	// round trip
+	var decoded testStructValues
+	err = DecodeValues(values, &decoded)
+	checkError(err)
+	src.Tags = []String{StringFrom("a"), StringFromPtr(nil)}
+	src.Ignored = String{}
+	assert.Equal(t, src, decoded, "EncodeValues() round trip fail")
+}
@@ -0,0 +1,41 @@
+package gomu
+
+import (
+	"encoding/xml"
+)
+
+// xsiNamespace is the XML Schema instance namespace, whose nil attribute marks a null element.
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// isXSINil reports whether start has the attribute xsi:nil="true".
+// The prefix is also accepted without a namespace declaration.
+func isXSINil(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "nil" && (attr.Name.Space == xsiNamespace || attr.Name.Space == "xsi") {
+			return attr.Value == "true" || attr.Value == "1"
+		}
+	}
+	return false
+}
+
+// encodeXSINil writes start as an empty element with the attribute xsi:nil="true".
+func encodeXSINil(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+		xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"},
+	)
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// decodeXMLText decodes the character data of the element start,
+// and reports whether it is null because it has xsi:nil="true".
+func decodeXMLText(d *xml.Decoder, start xml.StartElement) (text string, null bool, err error) {
+	if isXSINil(start) {
+		return "", true, d.Skip()
+	}
+	err = d.DecodeElement(&text, &start)
+	return
+}
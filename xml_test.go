@@ -0,0 +1,79 @@
+package gomu
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructXML struct {
+	XMLName xml.Name      `xml:"user"`
+	ID      Int           `xml:"id,attr"`
+	Kind    String        `xml:"kind,attr"`
+	Name    String        `xml:"name"`
+	Age     Int           `xml:"age"`
+	Ratio   Float         `xml:"ratio"`
+	Active  Bool          `xml:"active"`
+	Born    Time          `xml:"born"`
+	Nick    String        `xml:"nick"`
+	Score   Nullable[int] `xml:"score"`
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	data := `<user id="1" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+	<name>foo</name>
+	<age xsi:nil="true"/>
+	<ratio> 1.5 </ratio>
+	<active>1</active>
+	<born>2020-01-02T03:04:05Z</born>
+	<nick></nick>
+	<score></score>
+</user>`
+	var target testStructXML
+	err := xml.Unmarshal([]byte(data), &target)
+	checkError(err)
+	assert.Equal(t, IntFrom(1), target.ID, "ID fail")
+	assert.False(t, target.Kind.Valid, "Kind fail")
+	assert.Equal(t, StringFrom("foo"), target.Name, "Name fail")
+	assert.Equal(t, NewInt(0, true, true), target.Age, "Age fail")
+	assert.Equal(t, FloatFrom(1.5), target.Ratio, "Ratio fail")
+	assert.Equal(t, BoolFrom(true), target.Active, "Active fail")
+	assert.Equal(t, TimeFrom(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), target.Born, "Born fail")
+	assert.Equal(t, StringFrom(""), target.Nick, "Nick fail")
+	assert.Equal(t, NewNullable(0, true, true), target.Score, "Score fail")
+
+	// prefix without namespace declaration
+	err = xml.Unmarshal([]byte(`<user><name xsi:nil="true"></name></user>`), &target)
+	checkError(err)
+	assert.Equal(t, NewString("", true, true), target.Name, "Name xsi:nil fail")
+
+	err = xml.Unmarshal([]byte(`<user><age>x</age></user>`), &target)
+	assert.Error(t, err, "invalid Int fail")
+}
+
+func TestMarshalXML(t *testing.T) {
+	src := testStructXML{
+		ID:     IntFrom(1),
+		Kind:   NewString("", true, true),
+		Name:   StringFrom("foo"),
+		Age:    NewInt(0, true, true),
+		Active: BoolFrom(false),
+		Score:  NullableFrom(3),
+	}
+	target, err := xml.Marshal(src)
+	checkError(err)
+	expect := `<user id="1"><name>foo</name>` +
+		`<age xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></age>` +
+		`<active>false</active><score>3</score></user>`
+	assert.Equal(t, expect, string(target), "Marshal fail")
+
+	var round testStructXML
+	err = xml.Unmarshal(target, &round)
+	checkError(err)
+	assert.Equal(t, src.Age, round.Age, "round trip Age fail")
+	assert.Equal(t, src.Name, round.Name, "round trip Name fail")
+	assert.False(t, round.Kind.Valid, "round trip Kind fail")
+	assert.False(t, round.Born.Valid, "round trip Born fail")
+}
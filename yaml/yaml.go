@@ -0,0 +1,315 @@
+// Package yaml encodes and decodes YAML documents holding gomu values with gopkg.in/yaml.v3.
+//
+// gopkg.in/yaml.v3 leaves a gomu value unassigned for null, and encodes an unassigned one as null
+// unless its field has the omitempty option. Unmarshal sets gomu values to Null for null and ~,
+// and Marshal omits unassigned gomu values like gomu.Marshal does for JSON.
+package yaml
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+
+	"github.com/hapoon/gomu"
+	"github.com/hapoon/gomu/internal/structtag"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Unmarshal decodes the first document found within data into v like yaml.v3's Unmarshal,
+// except that gomu values whose key is set to null or ~ are set to Null.
+// gomu values whose key is missing are left unassigned (Valid is false).
+func Unmarshal(data []byte, v interface{}) error {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	if err := doc.Decode(v); err != nil {
+		return err
+	}
+	return setNulls(&doc, reflect.ValueOf(v))
+}
+
+// Marshal returns the YAML encoding of v like yaml.v3's Marshal,
+// except that struct fields and map entries holding a gomu value that is not Valid are omitted.
+// Null gomu values are still encoded as null.
+func Marshal(v interface{}) ([]byte, error) {
+	n, err := encodeNode(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return yamlv3.Marshal(n)
+}
+
+var (
+	nodeType          = reflect.TypeOf(yamlv3.Node{})
+	yamlMarshalerType = reflect.TypeOf((*yamlv3.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func isNull(n *yamlv3.Node) bool {
+	return n.Kind == yamlv3.ScalarNode && n.ShortTag() == "!!null"
+}
+
+// setNull sets the gomu value v to Null by decoding the null node n with its UnmarshalYAML method.
+func setNull(n *yamlv3.Node, v reflect.Value) error {
+	u := v.Addr().Interface().(interface {
+		UnmarshalYAML(unmarshal func(interface{}) error) error
+	})
+	return u.UnmarshalYAML(n.Decode)
+}
+
+func setNulls(n *yamlv3.Node, v reflect.Value) error {
+	switch n.Kind {
+	case yamlv3.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil
+		}
+		return setNulls(n.Content[0], v)
+	case yamlv3.AliasNode:
+		return setNulls(n.Alias, v)
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch {
+	case n.Kind == yamlv3.MappingNode && v.Kind() == reflect.Struct && !gomu.IsType(v.Type()):
+		fields := structFields(v.Type())
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			f, ok := fields[n.Content[i].Value]
+			if !ok {
+				continue
+			}
+			if err := setValueNulls(n.Content[i+1], v.FieldByIndex(f.index)); err != nil {
+				return err
+			}
+		}
+	case n.Kind == yamlv3.MappingNode && v.Kind() == reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := reflect.New(v.Type().Key())
+			if err := n.Content[i].Decode(k.Interface()); err != nil {
+				return err
+			}
+			e := reflect.New(v.Type().Elem()).Elem()
+			if cur := v.MapIndex(k.Elem()); cur.IsValid() {
+				e.Set(cur)
+			}
+			if err := setValueNulls(n.Content[i+1], e); err != nil {
+				return err
+			}
+			v.SetMapIndex(k.Elem(), e)
+		}
+	case n.Kind == yamlv3.SequenceNode && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		return setSequenceNulls(n, v)
+	}
+	return nil
+}
+
+// setSequenceNulls puts the Null elements of sequence n back into v,
+// since yaml.v3 drops null elements that cannot be set to nil.
+func setSequenceNulls(n *yamlv3.Node, v reflect.Value) error {
+	et := v.Type().Elem()
+	elems := make([]reflect.Value, 0, len(n.Content))
+	j := 0
+	for _, en := range n.Content {
+		if en.Kind == yamlv3.AliasNode {
+			en = en.Alias
+		}
+		if isNull(en) && !isNillable(et.Kind()) {
+			if gomu.IsType(et) {
+				e := reflect.New(et).Elem()
+				if err := setNull(en, e); err != nil {
+					return err
+				}
+				elems = append(elems, e)
+			}
+			continue
+		}
+		if j >= v.Len() {
+			break
+		}
+		e := reflect.New(et).Elem()
+		e.Set(v.Index(j))
+		j++
+		if err := setNulls(en, e); err != nil {
+			return err
+		}
+		elems = append(elems, e)
+	}
+	if v.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, e := range elems {
+			s.Index(i).Set(e)
+		}
+		v.Set(s)
+		return nil
+	}
+	for i := 0; i < len(elems) && i < v.Len(); i++ {
+		v.Index(i).Set(elems[i])
+	}
+	return nil
+}
+
+func isNillable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func setValueNulls(n *yamlv3.Node, v reflect.Value) error {
+	if n.Kind == yamlv3.AliasNode {
+		n = n.Alias
+	}
+	if isNull(n) {
+		if gomu.IsType(v.Type()) {
+			return setNull(n, v)
+		}
+		return nil
+	}
+	return setNulls(n, v)
+}
+
+type field struct {
+	name  string
+	index []int
+	opts  string
+}
+
+// structFields returns the fields of struct type t by their key, following the rules of yaml.v3:
+// the key is the name in the `yaml` tag or the lowercased field name,
+// and the fields of a struct with the inline option are promoted.
+func structFields(t reflect.Type) map[string]field {
+	fields := make(map[string]field)
+	for _, f := range structFieldList(t, nil) {
+		fields[f.name] = f
+	}
+	return fields
+}
+
+func structFieldList(t reflect.Type, index []int) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag := sf.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts := structtag.Parse(tag)
+		idx := append(append([]int(nil), index...), i)
+		if structtag.HasOption(opts, "inline") && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, structFieldList(sf.Type, idx)...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		fields = append(fields, field{name: name, index: idx, opts: opts})
+	}
+	return fields
+}
+
+func encodeNode(v reflect.Value) (*yamlv3.Node, error) {
+	if !v.IsValid() {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	if v.Type() == nodeType || v.Type().Implements(yamlMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return encodeDefault(v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return encodeNode(reflect.Value{})
+		}
+		return encodeNode(v.Elem())
+	case reflect.Struct:
+		n := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		for _, f := range structFieldList(v.Type(), nil) {
+			fv := v.FieldByIndex(f.index)
+			if gomu.IsUnassigned(fv) || structtag.HasOption(f.opts, "omitempty") && isEmpty(fv) {
+				continue
+			}
+			vn, err := encodeNode(fv)
+			if err != nil {
+				return nil, err
+			}
+			if structtag.HasOption(f.opts, "flow") {
+				vn.Style |= yamlv3.FlowStyle
+			}
+			n.Content = append(n.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: f.name}, vn)
+		}
+		return n, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return encodeDefault(v)
+		}
+		// A map of nodes is encoded by yaml.v3, which sorts the keys like it does for any other map.
+		m := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), reflect.PtrTo(nodeType)), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if gomu.IsUnassigned(iter.Value()) {
+				continue
+			}
+			vn, err := encodeNode(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			m.SetMapIndex(iter.Key(), reflect.ValueOf(vn))
+		}
+		return encodeDefault(m)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return encodeDefault(v)
+		}
+		n := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		for i := 0; i < v.Len(); i++ {
+			en, err := encodeNode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, en)
+		}
+		return n, nil
+	default:
+		return encodeDefault(v)
+	}
+}
+
+func encodeDefault(v reflect.Value) (*yamlv3.Node, error) {
+	n := new(yamlv3.Node)
+	if err := n.Encode(v.Interface()); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// isEmpty reports whether v is empty in the sense of the yaml omitempty option.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,93 @@
+package yaml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hapoon/gomu"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStructYAMLBase struct {
+	ID gomu.Int `yaml:"id"`
+}
+
+type testStructYAML struct {
+	testStructYAMLBase `yaml:",inline"`
+	Name               gomu.String         `yaml:"name"`
+	Age                gomu.Int            `yaml:"age"`
+	Ratio              gomu.Float          `yaml:"ratio"`
+	Active             gomu.Bool           `yaml:"active"`
+	Born               gomu.Time           `yaml:"born"`
+	Tags               []gomu.String       `yaml:"tags"`
+	Labels             map[string]gomu.Int `yaml:"labels"`
+	Retries            gomu.Nullable[int]  `yaml:"retries"`
+	Child              *testStructYAMLBase `yaml:"child"`
+	Plain              string              `yaml:"plain,omitempty"`
+	Note               gomu.String         `yaml:"note,omitempty"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := []byte(`
+id: 1
+name: foo
+age: ~
+ratio: null
+active: true
+born: 2020-01-02T03:04:05Z
+tags: [a, null]
+labels: {x: 1, y: null}
+child: {id: null}
+`)
+	var target testStructYAML
+	err := Unmarshal(data, &target)
+	assert.NoError(t, err, "Unmarshal fail")
+	assert.Equal(t, gomu.IntFrom(1), target.ID, "ID fail")
+	assert.Equal(t, gomu.StringFrom("foo"), target.Name, "Name fail")
+	assert.Equal(t, gomu.NewInt(0, true, true), target.Age, "Age fail")
+	assert.Equal(t, gomu.NewFloat(0, true, true), target.Ratio, "Ratio fail")
+	assert.Equal(t, gomu.BoolFrom(true), target.Active, "Active fail")
+	assert.Equal(t, gomu.TimeFrom(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), target.Born, "Born fail")
+	assert.Equal(t, []gomu.String{gomu.StringFrom("a"), gomu.NewString("", true, true)}, target.Tags, "Tags fail")
+	assert.Equal(t, map[string]gomu.Int{"x": gomu.IntFrom(1), "y": gomu.NewInt(0, true, true)}, target.Labels, "Labels fail")
+	assert.False(t, target.Retries.Valid, "Retries fail")
+	assert.Equal(t, gomu.NewInt(0, true, true), target.Child.ID, "Child fail")
+	assert.False(t, target.Note.Valid, "Note fail")
+
+	err = Unmarshal([]byte("name: [1"), &target)
+	assert.Error(t, err, "syntax error fail")
+
+	err = Unmarshal([]byte("age: x"), &target)
+	assert.Error(t, err, "type error fail")
+}
+
+func TestMarshal(t *testing.T) {
+	src := testStructYAML{
+		testStructYAMLBase: testStructYAMLBase{ID: gomu.IntFrom(1)},
+		Name:               gomu.StringFrom("foo"),
+		Age:                gomu.NewInt(0, true, true),
+		Tags:               []gomu.String{gomu.StringFrom("a")},
+		Labels:             map[string]gomu.Int{"y": gomu.IntFrom(2), "x": {}, "w": gomu.NewInt(0, true, true)},
+		Retries:            gomu.NullableFrom(3),
+	}
+	target, err := Marshal(src)
+	assert.NoError(t, err, "Marshal fail")
+	expect := `id: 1
+name: foo
+age: null
+tags:
+    - a
+labels:
+    w: null
+    "y": 2
+retries: 3
+child: null
+`
+	assert.Equal(t, expect, string(target), "Marshal fail")
+
+	var round testStructYAML
+	err = Unmarshal(target, &round)
+	assert.NoError(t, err, "round trip fail")
+	assert.Equal(t, src.Age, round.Age, "round trip Age fail")
+	assert.False(t, round.Ratio.Valid, "round trip Ratio fail")
+}